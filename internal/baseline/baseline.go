@@ -0,0 +1,149 @@
+// Project:   macbash
+// File:      internal/baseline/baseline.go
+// Purpose:   Suppress preexisting findings via a JSON baseline snapshot
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package baseline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+const baselineVersion = 1
+
+// Baseline is a snapshot of previously-known matches, keyed by a stable
+// fingerprint so reformatting or lines shifting elsewhere in the file
+// doesn't make a preexisting finding look new.
+type Baseline struct {
+	Version int     `json:"version"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry records one previously-accepted finding. LineHash captures the
+// matched line's content at baseline time for diagnostics; matching a
+// current scan against the baseline is by Fingerprint alone, since Line
+// itself shifts with unrelated edits.
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"rule_id"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	LineHash    string `json:"line_hash"`
+}
+
+// Fingerprint derives a stable ID for a match from its rule, file, and
+// matched text, normalized so incidental whitespace changes around the
+// match don't produce a different fingerprint.
+func Fingerprint(ruleID, file, matchedStr string) string {
+	sum := sha256.Sum256([]byte(ruleID + "\x00" + file + "\x00" + normalize(matchedStr)))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalize(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func lineHash(content string) string {
+	sum := sha256.Sum256([]byte(normalize(content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// FromMatches builds a Baseline capturing every match. matches must already
+// carry relative, portable File paths (see cli.relativizeMatchPaths) so the
+// baseline stays valid across machines and checkouts.
+func FromMatches(matches []rules.Match) *Baseline {
+	b := &Baseline{Version: baselineVersion, Entries: make([]Entry, len(matches))}
+	for i, m := range matches {
+		b.Entries[i] = Entry{
+			Fingerprint: Fingerprint(m.Rule.ID, m.File, m.MatchedStr),
+			RuleID:      m.Rule.ID,
+			File:        m.File,
+			Line:        m.Line,
+			LineHash:    lineHash(m.Content),
+		}
+	}
+	return b
+}
+
+// Write regenerates the baseline file at path from matches.
+func Write(path string, matches []rules.Match) error {
+	data, err := json.MarshalIndent(FromMatches(matches), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding baseline: %w", err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Load reads a baseline file written by Write.
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading baseline %s: %w", path, err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// Filter drops matches whose fingerprint already appears in b, leaving only
+// newly introduced findings.
+func (b *Baseline) Filter(matches []rules.Match) []rules.Match {
+	if b == nil || len(b.Entries) == 0 {
+		return matches
+	}
+	known := b.fingerprints()
+
+	out := make([]rules.Match, 0, len(matches))
+	for _, m := range matches {
+		if known[Fingerprint(m.Rule.ID, m.File, m.MatchedStr)] {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// StaleFingerprints returns baseline entries whose fingerprint no longer
+// appears among matches - findings the baseline suppresses that the
+// codebase has since fixed or removed. --baseline-strict treats a
+// non-empty result as an error, so stale entries get cleaned up with
+// `macbash baseline update` instead of accumulating forever.
+func (b *Baseline) StaleFingerprints(matches []rules.Match) []string {
+	if b == nil {
+		return nil
+	}
+
+	current := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		current[Fingerprint(m.Rule.ID, m.File, m.MatchedStr)] = true
+	}
+
+	var stale []string
+	for _, e := range b.Entries {
+		if !current[e.Fingerprint] {
+			stale = append(stale, e.Fingerprint)
+		}
+	}
+	return stale
+}
+
+func (b *Baseline) fingerprints() map[string]bool {
+	set := make(map[string]bool, len(b.Entries))
+	for _, e := range b.Entries {
+		set[e.Fingerprint] = true
+	}
+	return set
+}
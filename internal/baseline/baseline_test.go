@@ -0,0 +1,102 @@
+// Project:   macbash
+// File:      internal/baseline/baseline_test.go
+// Purpose:   Tests for baseline write/load/filter
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+func TestWriteLoadFilter(t *testing.T) {
+	rule := &rules.Rule{ID: "sed-inplace-no-backup", Name: "sed -i without backup"}
+	matches := []rules.Match{
+		{Rule: rule, File: "bin/deploy.sh", Line: 10, Content: "sed -i 's/a/b/' f", MatchedStr: "sed -i"},
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "baseline.json")
+
+	if err := Write(path, matches); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(loaded.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(loaded.Entries))
+	}
+
+	filtered := loaded.Filter(matches)
+	if len(filtered) != 0 {
+		t.Fatalf("expected baselined match to be filtered out, got %d", len(filtered))
+	}
+}
+
+func TestFilter_ShiftedLineStillMatches(t *testing.T) {
+	rule := &rules.Rule{ID: "sed-inplace-no-backup", Name: "sed -i without backup"}
+	baselined := []rules.Match{
+		{Rule: rule, File: "bin/deploy.sh", Line: 10, Content: "sed -i 's/a/b/' f", MatchedStr: "sed -i"},
+	}
+	b := FromMatches(baselined)
+
+	// Same rule, file, and matched text, but shifted to a different line
+	// (e.g. because an earlier line was added) - this must still be
+	// recognized as the same preexisting finding.
+	shifted := []rules.Match{
+		{Rule: rule, File: "bin/deploy.sh", Line: 25, Content: "sed -i 's/a/b/' f", MatchedStr: "sed -i"},
+	}
+
+	out := b.Filter(shifted)
+	if len(out) != 0 {
+		t.Fatalf("expected match to still be recognized after a line shift, got %d", len(out))
+	}
+}
+
+func TestFilter_NewFindingSurvives(t *testing.T) {
+	rule := &rules.Rule{ID: "sed-inplace-no-backup", Name: "sed -i without backup"}
+	b := FromMatches([]rules.Match{
+		{Rule: rule, File: "bin/deploy.sh", Line: 10, MatchedStr: "sed -i"},
+	})
+
+	newMatch := rules.Match{Rule: rule, File: "bin/other.sh", Line: 3, MatchedStr: "sed -i"}
+	out := b.Filter([]rules.Match{newMatch})
+	if len(out) != 1 {
+		t.Fatalf("expected the new finding to survive filtering, got %d", len(out))
+	}
+}
+
+func TestStaleFingerprints(t *testing.T) {
+	rule := &rules.Rule{ID: "sed-inplace-no-backup", Name: "sed -i without backup"}
+	b := FromMatches([]rules.Match{
+		{Rule: rule, File: "bin/deploy.sh", Line: 10, MatchedStr: "sed -i"},
+	})
+
+	stale := b.StaleFingerprints(nil)
+	if len(stale) != 1 {
+		t.Fatalf("expected 1 stale fingerprint when the finding is gone, got %d", len(stale))
+	}
+
+	stillPresent := []rules.Match{{Rule: rule, File: "bin/deploy.sh", Line: 10, MatchedStr: "sed -i"}}
+	if stale := b.StaleFingerprints(stillPresent); len(stale) != 0 {
+		t.Fatalf("expected no stale fingerprints when the finding persists, got %d", len(stale))
+	}
+}
+
+func TestFilter_NilBaseline(t *testing.T) {
+	var b *Baseline
+	matches := []rules.Match{{Rule: &rules.Rule{ID: "x"}, File: "f", MatchedStr: "y"}}
+	out := b.Filter(matches)
+	if len(out) != 1 {
+		t.Fatalf("expected nil baseline to be a no-op, got %d", len(out))
+	}
+}
@@ -0,0 +1,95 @@
+// Project:   macbash
+// File:      internal/cli/baseline_cmd.go
+// Purpose:   `macbash baseline write`/`update` subcommands
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hypersec-io/macbash/internal/baseline"
+	"github.com/hypersec-io/macbash/internal/scanner"
+)
+
+// baselineOutPath is the file the baseline subcommands write. It is kept
+// apart from the root command's baselinePath so that this flag's default
+// does not turn --baseline on for a plain check.
+var baselineOutPath string
+
+// newBaselineCmd groups the subcommands that regenerate a --baseline file
+// from the current state of the tree: write for first adoption, update
+// after intentionally fixing or introducing findings. Both run the same
+// scan-and-overwrite logic; the two names exist so the workflow reads
+// naturally at either step.
+func newBaselineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "baseline",
+		Short: "Manage the --baseline file of accepted findings",
+	}
+
+	write := &cobra.Command{
+		Use:   "write [files...]",
+		Short: "Write a baseline capturing every current finding",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runBaselineWrite,
+	}
+	update := &cobra.Command{
+		Use:   "update [files...]",
+		Short: "Regenerate the baseline to match the current tree",
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runBaselineWrite,
+	}
+
+	for _, sub := range []*cobra.Command{write, update} {
+		sub.Flags().StringVarP(&configFile, "config", "c", "", "Path to custom rules YAML file")
+		sub.Flags().StringVar(&rulesDir, "rules-dir", "", "Directory of YAML rule files to layer on top of built-ins")
+		sub.Flags().StringVar(&root, "root", "", "Root to report paths relative to (default: git repo root, else cwd)")
+		sub.Flags().StringVar(&baselineOutPath, "baseline", ".macbash-baseline.json", "Path to the baseline JSON file to write")
+		cmd.AddCommand(sub)
+	}
+
+	return cmd
+}
+
+func runBaselineWrite(cmd *cobra.Command, args []string) error {
+	for _, file := range args {
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			return fmt.Errorf("file not found: %s", file)
+		}
+	}
+
+	ruleSet, err := loadRules()
+	if err != nil {
+		return fmt.Errorf("loading rules: %w", err)
+	}
+
+	s, err := scanner.New(ruleSet)
+	if err != nil {
+		return fmt.Errorf("creating scanner: %w", err)
+	}
+
+	matches, err := s.ScanFiles(args)
+	if err != nil {
+		return fmt.Errorf("scanning files: %w", err)
+	}
+
+	reportRoot, err := reportRoot()
+	if err != nil {
+		return fmt.Errorf("resolving report root: %w", err)
+	}
+	matches = relativizeMatchPaths(matches, reportRoot)
+
+	if err := baseline.Write(baselineOutPath, matches); err != nil {
+		return fmt.Errorf("writing baseline: %w", err)
+	}
+
+	fmt.Printf("Wrote %d finding(s) to %s\n", len(matches), baselineOutPath)
+	return nil
+}
@@ -9,12 +9,17 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/hypersec-io/macbash/internal/baseline"
+	"github.com/hypersec-io/macbash/internal/diag"
 	"github.com/hypersec-io/macbash/internal/fixer"
 	"github.com/hypersec-io/macbash/internal/output"
 	"github.com/hypersec-io/macbash/internal/rules"
@@ -22,14 +27,20 @@ import (
 )
 
 var (
-	configFile  string
-	fix         bool
-	write       bool
-	outputPath  string
-	severity    string
-	format      string
-	showVersion bool
-	dryRun      bool
+	configFile     string
+	rulesDir       string
+	root           string
+	fix            bool
+	write          bool
+	outputPath     string
+	severity       string
+	format         string
+	showVersion    bool
+	dryRun         bool
+	showAutofix    bool
+	interactive    bool
+	baselinePath   string
+	baselineStrict bool
 
 	appVersion   string
 	appCommit    string
@@ -65,9 +76,22 @@ Examples:
   # Use custom rules file
   macbash --config rules.yaml script.sh
 
+  # Layer a directory of rule packs on top of built-ins
+  macbash --rules-dir ./rules.d/ script.sh
+
   # Output as JSON for CI integration
   macbash --format json script.sh
 
+  # Output as SARIF for GitHub/GitLab code scanning
+  macbash --format sarif script.sh
+
+  # Output as Code Climate JSON for GitLab CI's Code Quality widget
+  macbash --format codeclimate script.sh
+
+  # Adopt macbash on an existing codebase: only fail CI on new issues
+  macbash baseline write script.sh
+  macbash --baseline .macbash-baseline.json script.sh
+
 Output Modes:
   (default)     Check only, report issues to stdout
   -w, --write   Fix and overwrite original files in-place
@@ -79,22 +103,32 @@ Exit Codes:
   0 - No issues found (or all fixed with -w/-o)
   1 - Errors found (unfixable or check-only mode)
   2 - Only warnings found (with --severity=warning)`,
+		Args:          cobra.ArbitraryArgs,
 		RunE:          runCheck,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
 
 	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Path to custom rules YAML file")
+	cmd.Flags().StringVar(&rulesDir, "rules-dir", "", "Directory of YAML rule files to layer on top of built-ins")
+	cmd.Flags().StringVar(&root, "root", "", "Root to report paths relative to (default: git repo root, else cwd)")
 	cmd.Flags().BoolVarP(&write, "write", "w", false, "Fix and overwrite files in-place")
 	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output path (file for single input, directory for multiple)")
 	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview fixes without writing (use with -w or -o)")
+	cmd.Flags().BoolVar(&showAutofix, "show-autofix", false, "Print what would change as unified diffs, without writing")
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "Prompt per-hunk before applying each fix (y/n/a/q)")
 	cmd.Flags().StringVarP(&severity, "severity", "s", "warning", "Minimum severity to report: error, warning, info")
-	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json, sarif, checkstyle, codeclimate")
 	cmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Show version information")
 
 	cmd.Flags().BoolVarP(&fix, "fix", "f", false, "Deprecated: use -w or -o instead")
 	_ = cmd.Flags().MarkDeprecated("fix", "use -w (--write) or -o (--output) instead")
 
+	cmd.Flags().StringVar(&baselinePath, "baseline", "", "Path to a baseline JSON file; matches it contains are suppressed")
+	cmd.Flags().BoolVar(&baselineStrict, "baseline-strict", false, "Fail if the baseline contains fingerprints no longer found")
+
+	cmd.AddCommand(newBaselineCmd())
+
 	return cmd
 }
 
@@ -138,8 +172,10 @@ func runCheck(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid severity %q: must be error, warning, or info", severity)
 	}
 
-	if format != "text" && format != "json" {
-		return fmt.Errorf("invalid format %q: must be text or json", format)
+	switch format {
+	case "text", "json", "sarif", "checkstyle", "codeclimate":
+	default:
+		return fmt.Errorf("invalid format %q: must be text, json, sarif, checkstyle, or codeclimate", format)
 	}
 
 	for _, file := range args {
@@ -165,17 +201,55 @@ func runCheck(cmd *cobra.Command, args []string) error {
 
 	matches = scanner.FilterBySeverity(matches, minSeverity)
 
-	if write || outputPath != "" {
+	if write || outputPath != "" || showAutofix {
 		return runFix(args, matches, minSeverity)
 	}
 
+	reportRoot, err := reportRoot()
+	if err != nil {
+		return fmt.Errorf("resolving report root: %w", err)
+	}
+	matches = relativizeMatchPaths(matches, reportRoot)
+
+	if baselinePath != "" {
+		bl, err := baseline.Load(baselinePath)
+		if err != nil {
+			return fmt.Errorf("loading baseline: %w", err)
+		}
+		if baselineStrict {
+			// Staleness is judged against every finding, not just those at
+			// or above --severity, since `baseline write` records them all.
+			all, err := s.ScanFiles(args)
+			if err != nil {
+				return fmt.Errorf("scanning files: %w", err)
+			}
+			if stale := bl.StaleFingerprints(relativizeMatchPaths(all, reportRoot)); len(stale) > 0 {
+				return fmt.Errorf("baseline is stale: %d finding(s) no longer present; run 'macbash baseline update' to refresh", len(stale))
+			}
+		}
+		matches = bl.Filter(matches)
+	}
+
 	formatter := output.New(os.Stdout)
+	formatter.SetVersion(appVersion)
 
 	switch format {
 	case "json":
 		if err := formatter.JSON(matches); err != nil {
 			return fmt.Errorf("outputting JSON: %w", err)
 		}
+	case "sarif":
+		if err := formatter.SARIF(matches); err != nil {
+			return fmt.Errorf("outputting SARIF: %w", err)
+		}
+	case "checkstyle":
+		if err := formatter.Checkstyle(matches); err != nil {
+			return fmt.Errorf("outputting Checkstyle: %w", err)
+		}
+	case "codeclimate":
+		if err := formatter.CodeClimate(matches); err != nil {
+			return fmt.Errorf("outputting Code Climate: %w", err)
+		}
 	default:
 		formatter.Text(matches)
 	}
@@ -191,10 +265,27 @@ func runFix(inputFiles []string, matches []rules.Match, _ rules.Severity) error
 	f := fixer.New()
 
 	var fixedCount, unfixedCount int
+	var reader *bufio.Reader
+	if interactive {
+		reader = bufio.NewReader(os.Stdin)
+	}
+
+	// --show-autofix is always a preview: it exists to print diffs, not to
+	// apply them, regardless of whether -w/-o was also passed.
+	effectiveDryRun := dryRun || showAutofix
 
 	for _, inputFile := range inputFiles {
 		fileMatches := filterMatchesByFile(matches, inputFile)
 		outPath := determineOutputPath(inputFile, inputFiles)
+
+		if interactive {
+			accepted, err := promptFixes(f, inputFile, fileMatches, reader)
+			if err != nil {
+				return fmt.Errorf("prompting fixes for %s: %w", inputFile, err)
+			}
+			fileMatches = accepted
+		}
+
 		result, err := f.FixFile(inputFile, fileMatches)
 		if err != nil {
 			return fmt.Errorf("fixing %s: %w", inputFile, err)
@@ -211,7 +302,7 @@ func runFix(inputFiles []string, matches []rules.Match, _ rules.Severity) error
 			continue
 		}
 
-		if !dryRun {
+		if !effectiveDryRun {
 			if err := writeOutput(outPath, result.Content); err != nil {
 				return fmt.Errorf("writing %s: %w", outPath, err)
 			}
@@ -230,9 +321,20 @@ func runFix(inputFiles []string, matches []rules.Match, _ rules.Severity) error
 			fmt.Printf("[dry-run] Would fix %s -> %s (%d fixes, %d unfixable)\n",
 				inputFile, outPath, result.FixedCount, result.UnfixedCount)
 			if result.FixedCount > 0 {
-				fmt.Println("--- Preview of changes ---")
-				fmt.Println(result.Content)
-				fmt.Println("--- End preview ---")
+				if showAutofix {
+					formatter := output.New(os.Stdout)
+					if format == "json" {
+						if err := formatter.DiagnosticsJSON(result.Diagnostics); err != nil {
+							return fmt.Errorf("outputting JSON: %w", err)
+						}
+					} else {
+						formatter.Diagnostics(result.Diagnostics)
+					}
+				} else {
+					fmt.Println("--- Preview of changes ---")
+					fmt.Println(result.Content)
+					fmt.Println("--- End preview ---")
+				}
 			}
 		}
 	}
@@ -246,6 +348,77 @@ func runFix(inputFiles []string, matches []rules.Match, _ rules.Severity) error
 	return nil
 }
 
+// promptFixes walks the auto-fixable matches in fileMatches one at a time,
+// showing each as a unified diff hunk and asking whether to apply it.
+// Matches with no automatic fix pass through untouched so they still show
+// up as unfixable in the summary. "a" accepts every remaining fixable match
+// without prompting again; "q" rejects every remaining one.
+//
+// FixFunction matches (e.g. "insert-pipefail") are file-level: every match
+// sharing the same FixFunction is addressed by one fix regardless of which
+// occurrence triggered it, so only the first one is shown - the rest get
+// the same answer without re-prompting.
+func promptFixes(f *fixer.Fixer, path string, fileMatches []rules.Match, reader *bufio.Reader) ([]rules.Match, error) {
+	var accepted []rules.Match
+	acceptRest, rejectRest := false, false
+	fileFixDecisions := make(map[string]bool)
+
+	for _, m := range fileMatches {
+		fixable := m.Rule.FixType == rules.FixReplace || m.Rule.FixType == rules.FixTransform || m.Rule.FixType == rules.FixFunction
+		if !fixable || acceptRest {
+			accepted = append(accepted, m)
+			continue
+		}
+		if rejectRest {
+			continue
+		}
+
+		if m.Rule.FixType == rules.FixFunction {
+			if decided, seen := fileFixDecisions[m.Rule.FixFunction]; seen {
+				if decided {
+					accepted = append(accepted, m)
+				}
+				continue
+			}
+		}
+
+		var d diag.Diagnostic
+		var err error
+		if m.Rule.FixType == rules.FixFunction {
+			d, err = f.PreviewFileMatch(path, m)
+		} else {
+			d, err = f.PreviewMatch(path, m)
+		}
+		if err != nil {
+			accepted = append(accepted, m)
+			continue
+		}
+
+		fmt.Print(diag.FormatFile(path, []diag.Diagnostic{d}))
+		fmt.Print("Apply this fix? [y/n/a/q] ")
+
+		line, _ := reader.ReadString('\n')
+		accept := false
+		switch strings.TrimSpace(strings.ToLower(line)) {
+		case "y":
+			accept = true
+			accepted = append(accepted, m)
+		case "a":
+			accept = true
+			acceptRest = true
+			accepted = append(accepted, m)
+		case "q":
+			rejectRest = true
+		}
+
+		if m.Rule.FixType == rules.FixFunction {
+			fileFixDecisions[m.Rule.FixFunction] = accept
+		}
+	}
+
+	return accepted, nil
+}
+
 func determineOutputPath(inputFile string, allInputFiles []string) string {
 	if write {
 		return inputFile
@@ -278,21 +451,66 @@ func writeOutput(path, content string) error {
 	return os.WriteFile(path, []byte(content), 0o644)
 }
 
+// reportRoot resolves the directory that reported file paths should be made
+// relative to: the --root flag if given, otherwise the enclosing git repo's
+// top level if detectable, otherwise the current working directory.
+func reportRoot() (string, error) {
+	if root != "" {
+		return filepath.Abs(root)
+	}
+
+	if out, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	return os.Getwd()
+}
+
+// relativizeMatchPaths rewrites each Match.File to a forward-slash path
+// relative to root, so text/JSON/SARIF output stays clean in CI logs and
+// portable across machines. Files outside root (or any that can't be made
+// relative) are left as their original, unmodified value.
+func relativizeMatchPaths(matches []rules.Match, root string) []rules.Match {
+	out := make([]rules.Match, len(matches))
+	for i, m := range matches {
+		out[i] = m
+		abs, err := filepath.Abs(m.File)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		out[i].File = filepath.ToSlash(rel)
+	}
+	return out
+}
+
 func loadRules() (*rules.RuleSet, error) {
 	builtin, err := rules.LoadBuiltin()
 	if err != nil {
 		return nil, fmt.Errorf("loading builtin rules: %w", err)
 	}
 
+	var layerPaths []string
 	if configFile != "" {
-		custom, err := rules.LoadFromFile(configFile)
-		if err != nil {
-			return nil, fmt.Errorf("loading custom rules: %w", err)
-		}
-		return rules.Merge(builtin, custom), nil
+		layerPaths = append(layerPaths, configFile)
+	}
+	if rulesDir != "" {
+		layerPaths = append(layerPaths, rulesDir)
+	}
+
+	if len(layerPaths) == 0 {
+		return builtin, nil
+	}
+
+	layered, err := rules.LoadLayered(layerPaths...)
+	if err != nil {
+		return nil, fmt.Errorf("loading custom rules: %w", err)
 	}
 
-	return builtin, nil
+	return rules.Merge(builtin, layered), nil
 }
 
 func Execute(version, commit, buildTime string) error {
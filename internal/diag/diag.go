@@ -0,0 +1,58 @@
+// Project:   macbash
+// File:      internal/diag/diag.go
+// Purpose:   Structured diagnostic and fix records for the autofix subsystem
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package diag
+
+import (
+	"sort"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+// Fix is the before/after line slice for a diagnostic that can be applied
+// automatically. Before/After cover the same [StartLine, EndLine] range, so
+// a Fix whose After is shorter or longer than Before still renders as a
+// single unified diff hunk.
+type Fix struct {
+	Before []string
+	After  []string
+}
+
+// Diagnostic is one rule violation found in a file, optionally paired with
+// a Fix. Unlike rules.Match, which is scanner output, a Diagnostic is what
+// the fixer reports: it survives whether or not an automatic fix exists.
+type Diagnostic struct {
+	File      string
+	StartLine int
+	EndLine   int
+	Severity  rules.Severity
+	RuleID    string
+	Message   string
+	Fix       *Fix
+}
+
+// GroupByFile buckets diagnostics by file, preserving the order diagnostics
+// were appended within each file.
+func GroupByFile(diags []Diagnostic) map[string][]Diagnostic {
+	grouped := make(map[string][]Diagnostic)
+	for _, d := range diags {
+		grouped[d.File] = append(grouped[d.File], d)
+	}
+	return grouped
+}
+
+// SortedFiles returns the files present in a grouped diagnostic map in
+// stable, alphabetical order, so repeated runs print in the same order.
+func SortedFiles(grouped map[string][]Diagnostic) []string {
+	files := make([]string, 0, len(grouped))
+	for f := range grouped {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
@@ -0,0 +1,69 @@
+// Project:   macbash
+// File:      internal/diag/unified.go
+// Purpose:   Render diagnostics as unified diff hunks
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package diag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnifiedHunk renders a single unified diff hunk for a Fix anchored at
+// startLine. It omits file headers since callers print one header per file
+// and may follow it with several hunks.
+func UnifiedHunk(startLine int, fix *Fix) string {
+	if fix == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", startLine, len(fix.Before), startLine, len(fix.After))
+	for _, l := range fix.Before {
+		fmt.Fprintf(&b, "-%s\n", l)
+	}
+	for _, l := range fix.After {
+		fmt.Fprintf(&b, "+%s\n", l)
+	}
+
+	return b.String()
+}
+
+// FormatFile renders a pkglint-style block for one file: a header line
+// followed by every diagnostic, each as a rule/message summary and, when a
+// Fix is present, a unified diff hunk.
+func FormatFile(file string, diags []Diagnostic) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", file)
+
+	for _, d := range diags {
+		fmt.Fprintf(&b, "  %d:%d: %s [%s]\n", d.StartLine, d.EndLine, d.Message, d.RuleID)
+		if d.Fix != nil {
+			for _, line := range strings.Split(strings.TrimRight(UnifiedHunk(d.StartLine, d.Fix), "\n"), "\n") {
+				fmt.Fprintf(&b, "    %s\n", line)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Format renders every file's diagnostics in file order, as FormatFile
+// blocks separated by a blank line.
+func Format(diags []Diagnostic) string {
+	grouped := GroupByFile(diags)
+
+	var b strings.Builder
+	for i, file := range SortedFiles(grouped) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(FormatFile(file, grouped[file]))
+	}
+
+	return b.String()
+}
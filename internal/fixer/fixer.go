@@ -18,6 +18,7 @@ import (
 
 	"mvdan.cc/sh/v3/syntax"
 
+	"github.com/hypersec-io/macbash/internal/diag"
 	"github.com/hypersec-io/macbash/internal/rules"
 )
 
@@ -30,6 +31,7 @@ type Result struct {
 	FixedCount    int
 	UnfixedCount  int
 	Fixes         []AppliedFix
+	Diagnostics   []diag.Diagnostic
 	ValidationErr error
 }
 
@@ -47,6 +49,50 @@ func New() *Fixer {
 	}
 }
 
+// PreviewMatch computes the diagnostic for a single match in isolation,
+// without reading or writing the rest of the file. It's used by
+// --interactive to show one hunk at a time before the matches it covers are
+// folded into a FixFile call.
+func (f *Fixer) PreviewMatch(path string, m rules.Match) (diag.Diagnostic, error) {
+	_, _, diags, _ := f.fixLine(m.Content, []rules.Match{m})
+
+	if len(diags) == 0 {
+		return diag.Diagnostic{}, fmt.Errorf("no diagnostic produced for %s:%d", path, m.Line)
+	}
+
+	d := diags[0]
+	d.File = path
+	return d, nil
+}
+
+// PreviewFileMatch computes the diagnostic for a single file-level match
+// (FixType == FixFunction) in isolation, mirroring PreviewMatch for the
+// line-level case. It's used by --interactive so matches like
+// "insert-pipefail" are shown and prompted for like any other fix instead
+// of being silently applied by FixFile regardless of the user's answer.
+func (f *Fixer) PreviewFileMatch(path string, m rules.Match) (diag.Diagnostic, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return diag.Diagnostic{}, err
+	}
+	defer file.Close()
+
+	var outputLines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		outputLines = append(outputLines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return diag.Diagnostic{}, err
+	}
+
+	_, _, diags := insertPipefailFix(path, outputLines, []rules.Match{m})
+	if len(diags) == 0 {
+		return diag.Diagnostic{}, fmt.Errorf("no diagnostic produced for %s:%d", path, m.Line)
+	}
+	return diags[0], nil
+}
+
 func (f *Fixer) FixFile(path string, matches []rules.Match) (*Result, error) {
 	file, err := os.Open(path)
 	if err != nil {
@@ -54,8 +100,10 @@ func (f *Fixer) FixFile(path string, matches []rules.Match) (*Result, error) {
 	}
 	defer file.Close()
 
+	lineMatches, fileMatches := splitFileLevelMatches(matches)
+
 	matchesByLine := make(map[int][]rules.Match)
-	for _, m := range matches {
+	for _, m := range lineMatches {
 		matchesByLine[m.Line] = append(matchesByLine[m.Line], m)
 	}
 
@@ -75,10 +123,14 @@ func (f *Fixer) FixFile(path string, matches []rules.Match) (*Result, error) {
 		lineNum++
 		line := scanner.Text()
 
-		if lineMatches, ok := matchesByLine[lineNum]; ok {
-			fixedLine, fixes, unfixed := f.fixLine(line, lineMatches)
+		if lm, ok := matchesByLine[lineNum]; ok {
+			fixedLine, fixes, diags, unfixed := f.fixLine(line, lm)
+			for i := range diags {
+				diags[i].File = path
+			}
 			outputLines = append(outputLines, fixedLine)
 			result.Fixes = append(result.Fixes, fixes...)
+			result.Diagnostics = append(result.Diagnostics, diags...)
 			result.FixedCount += len(fixes)
 			result.UnfixedCount += unfixed
 		} else {
@@ -90,6 +142,15 @@ func (f *Fixer) FixFile(path string, matches []rules.Match) (*Result, error) {
 		return nil, err
 	}
 
+	if len(fileMatches) > 0 {
+		var fixes []AppliedFix
+		var diags []diag.Diagnostic
+		outputLines, fixes, diags = insertPipefailFix(path, outputLines, fileMatches)
+		result.Fixes = append(result.Fixes, fixes...)
+		result.Diagnostics = append(result.Diagnostics, diags...)
+		result.FixedCount += len(fixes)
+	}
+
 	result.Content = strings.Join(outputLines, "\n") + "\n"
 
 	if result.FixedCount > 0 {
@@ -101,7 +162,67 @@ func (f *Fixer) FixFile(path string, matches []rules.Match) (*Result, error) {
 	return &result, nil
 }
 
-func (f *Fixer) fixLine(line string, matches []rules.Match) (fixedLine string, fixes []AppliedFix, unfixed int) {
+// splitFileLevelMatches pulls out matches whose fix can't be expressed as a
+// same-line replacement - currently just "insert-pipefail", which touches a
+// line near the top of the file rather than the line it was reported on.
+func splitFileLevelMatches(matches []rules.Match) (lineMatches, fileMatches []rules.Match) {
+	for _, m := range matches {
+		if m.Rule.FixType == rules.FixFunction && m.Rule.FixFunction == "insert-pipefail" {
+			fileMatches = append(fileMatches, m)
+			continue
+		}
+		lineMatches = append(lineMatches, m)
+	}
+	return lineMatches, fileMatches
+}
+
+// insertPipefailFix adds "set -euo pipefail" once, after the shebang line if
+// there is one, to address every fileMatches entry at once - they all share
+// the same fix regardless of which pipeline each was reported against.
+func insertPipefailFix(path string, outputLines []string, fileMatches []rules.Match) ([]string, []AppliedFix, []diag.Diagnostic) {
+	const pipefailLine = "set -euo pipefail"
+
+	insertAt := 0
+	var before []string
+	if len(outputLines) > 0 && strings.HasPrefix(outputLines[0], "#!") {
+		insertAt = 1
+		before = []string{outputLines[0]}
+	}
+	after := append(append([]string{}, before...), pipefailLine)
+
+	fixed := make([]string, 0, len(outputLines)+1)
+	fixed = append(fixed, outputLines[:insertAt]...)
+	fixed = append(fixed, pipefailLine)
+	fixed = append(fixed, outputLines[insertAt:]...)
+
+	fix := &diag.Fix{Before: before, After: after}
+
+	fixes := make([]AppliedFix, 0, len(fileMatches))
+	diags := make([]diag.Diagnostic, 0, len(fileMatches))
+	for _, m := range fileMatches {
+		fixes = append(fixes, AppliedFix{
+			Line:     insertAt + 1,
+			RuleID:   m.Rule.ID,
+			Original: strings.Join(before, "\n"),
+			Fixed:    strings.Join(after, "\n"),
+		})
+		// Anchored where the fix applies (line 1), not at the pipeline, so
+		// the unified hunk for Fix points at the lines it rewrites.
+		diags = append(diags, diag.Diagnostic{
+			File:      path,
+			StartLine: 1,
+			EndLine:   1,
+			Severity:  m.Rule.Severity,
+			RuleID:    m.Rule.ID,
+			Message:   m.Rule.Name,
+			Fix:       fix,
+		})
+	}
+
+	return fixed, fixes, diags
+}
+
+func (f *Fixer) fixLine(line string, matches []rules.Match) (fixedLine string, fixes []AppliedFix, diags []diag.Diagnostic, unfixed int) {
 	fixedLine = line
 
 	for _, m := range matches {
@@ -113,6 +234,7 @@ func (f *Fixer) fixLine(line string, matches []rules.Match) (fixedLine string, f
 			re, err := f.getCompiledPattern(m.Rule)
 			if err != nil {
 				unfixed++
+				diags = append(diags, unfixedDiagnostic(m))
 				continue
 			}
 
@@ -124,23 +246,49 @@ func (f *Fixer) fixLine(line string, matches []rules.Match) (fixedLine string, f
 
 		default:
 			unfixed++
+			diags = append(diags, unfixedDiagnostic(m))
 			continue
 		}
 
 		if applied {
+			before := fixedLine
 			fixes = append(fixes, AppliedFix{
 				Line:     m.Line,
 				RuleID:   m.Rule.ID,
-				Original: fixedLine,
+				Original: before,
 				Fixed:    newResult,
 			})
+			diags = append(diags, diag.Diagnostic{
+				StartLine: m.Line,
+				EndLine:   m.Line,
+				Severity:  m.Rule.Severity,
+				RuleID:    m.Rule.ID,
+				Message:   m.Rule.Name,
+				Fix: &diag.Fix{
+					Before: []string{before},
+					After:  []string{newResult},
+				},
+			})
 			fixedLine = newResult
 		} else {
 			unfixed++
+			diags = append(diags, unfixedDiagnostic(m))
 		}
 	}
 
-	return fixedLine, fixes, unfixed
+	return fixedLine, fixes, diags, unfixed
+}
+
+// unfixedDiagnostic builds a Diagnostic with no Fix for a match that has no
+// automatic fix, or whose fix could not be applied.
+func unfixedDiagnostic(m rules.Match) diag.Diagnostic {
+	return diag.Diagnostic{
+		StartLine: m.Line,
+		EndLine:   m.Line,
+		Severity:  m.Rule.Severity,
+		RuleID:    m.Rule.ID,
+		Message:   m.Rule.Name,
+	}
 }
 
 func (f *Fixer) getCompiledPattern(rule *rules.Rule) (*regexp.Regexp, error) {
@@ -161,11 +309,55 @@ func (f *Fixer) applyTransform(line string, m *rules.Match) (string, bool) {
 	switch m.Rule.ID {
 	case "grep-perl-regex", "grep-only-matching-P":
 		return f.transformGrepPtoE(line, m)
+	case "negated-test-command", "negated-bracket-test", "negated-extended-test":
+		return f.transformNegatedTest(line, m)
 	default:
 		return line, false
 	}
 }
 
+var (
+	negatedUnaryTestRe      = regexp.MustCompile(`!\s*(test|\[\[|\[)\s+(-z|-n)\b`)
+	negatedComparisonTestRe = regexp.MustCompile(`!\s*(test|\[\[|\[)\s+(\S+)\s*(=|!=|-eq|-ne|-lt|-ge|-gt|-le)\s*(\S+)`)
+)
+
+var (
+	unaryTestFlip      = map[string]string{"-z": "-n", "-n": "-z"}
+	comparisonTestFlip = map[string]string{
+		"=": "!=", "!=": "=",
+		"-eq": "-ne", "-ne": "-eq",
+		"-lt": "-ge", "-ge": "-lt",
+		"-gt": "-le", "-le": "-gt",
+	}
+)
+
+// transformNegatedTest rewrites `! test -z X` (and the `[`/`[[` spellings)
+// to the positive form `test -n X`, and likewise for the comparison
+// operators. Negations with no positive opposite (e.g. `! test -d X`) are
+// left alone - the outer `!` is doing real work there.
+func (f *Fixer) transformNegatedTest(line string, _ *rules.Match) (string, bool) {
+	if negatedUnaryTestRe.MatchString(line) {
+		// ReplaceAllStringFunc inserts the returned string literally, unlike
+		// ReplaceAllString, which would reinterpret a captured operand like
+		// "$1" as a backreference instead of the variable reference it is.
+		fixed := negatedUnaryTestRe.ReplaceAllStringFunc(line, func(match string) string {
+			m := negatedUnaryTestRe.FindStringSubmatch(match)
+			return m[1] + " " + unaryTestFlip[m[2]]
+		})
+		return fixed, true
+	}
+
+	if negatedComparisonTestRe.MatchString(line) {
+		fixed := negatedComparisonTestRe.ReplaceAllStringFunc(line, func(match string) string {
+			m := negatedComparisonTestRe.FindStringSubmatch(match)
+			return m[1] + " " + m[2] + " " + comparisonTestFlip[m[3]] + " " + m[4]
+		})
+		return fixed, true
+	}
+
+	return line, false
+}
+
 // Converts simple grep -P to grep -E. Skips patterns with \K, lookbehinds, etc.
 func (f *Fixer) transformGrepPtoE(line string, _ *rules.Match) (string, bool) {
 	grepReSingle := regexp.MustCompile(`(grep\s+)(-[a-zA-Z]*P[a-zA-Z]*)(\s+)'([^']*)'`)
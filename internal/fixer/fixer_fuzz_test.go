@@ -0,0 +1,107 @@
+// Project:   macbash
+// File:      internal/fixer/fixer_fuzz_test.go
+// Purpose:   Fuzz the scan-fix round trip for corruption and non-convergence
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package fixer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+	"github.com/hypersec-io/macbash/internal/scanner"
+)
+
+// FuzzFixRoundTrip exercises the full scan -> fix pipeline against mutated
+// bash snippets. A regex-based FixReplace can produce output that still
+// parses but no longer means what the original script meant;
+// validateBashSyntax alone can't catch that, so this asserts three
+// invariants instead: the fixed content parses, re-scanning it doesn't
+// reproduce the same rule on the same line (convergence), and fixing it a
+// second time is a no-op (idempotency). The corpus under
+// testdata/fuzz/FuzzFixRoundTrip/ seeds known-tricky shapes: heredocs,
+// nested $(...), and quoted regexes containing \d.
+func FuzzFixRoundTrip(f *testing.F) {
+	seeds := []string{
+		"#!/bin/bash\necho hi\n",
+		"#!/bin/bash\nif ! test -z \"$1\"; then\n  echo set\nfi\n",
+		"#!/bin/bash\nif ! test \"$1\" = \"foo\"; then\n  echo mismatch\nfi\n",
+		"#!/bin/bash\ngrep -P '\\d+' file.txt\n",
+		"#!/bin/bash\ngrep error log.txt | while read -r line; do\n  echo \"$line\"\ndone\n",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	rs, err := rules.LoadBuiltin()
+	if err != nil {
+		f.Fatalf("LoadBuiltin() error = %v", err)
+	}
+
+	s, err := scanner.New(rs)
+	if err != nil {
+		f.Fatalf("scanner.New() error = %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "fuzz.sh")
+		writeFile(t, path, src)
+
+		matches, err := s.ScanFile(path)
+		if err != nil {
+			t.Fatalf("ScanFile(%q) error = %v", path, err)
+		}
+
+		fx := New()
+		first, err := fx.FixFile(path, matches)
+		if err != nil {
+			t.Fatalf("FixFile(%q) error = %v", path, err)
+		}
+
+		if first.FixedCount == 0 {
+			return
+		}
+
+		if first.ValidationErr != nil {
+			t.Fatalf("fixed content fails to parse: %v\n--- before ---\n%s\n--- after ---\n%s",
+				first.ValidationErr, src, first.Content)
+		}
+
+		writeFile(t, path, first.Content)
+
+		rescanned, err := s.ScanFile(path)
+		if err != nil {
+			t.Fatalf("re-scan error = %v", err)
+		}
+		for _, applied := range first.Fixes {
+			for _, m := range rescanned {
+				if m.Line == applied.Line && m.Rule.ID == applied.RuleID {
+					t.Fatalf("rule %s on line %d still matches after fix\nbefore: %q\nafter:  %q",
+						applied.RuleID, applied.Line, applied.Original, applied.Fixed)
+				}
+			}
+		}
+
+		second, err := fx.FixFile(path, rescanned)
+		if err != nil {
+			t.Fatalf("second FixFile(%q) error = %v", path, err)
+		}
+		if second.FixedCount != 0 {
+			t.Fatalf("fixer not idempotent: second pass applied %d more fix(es)\n--- first pass output ---\n%s\n--- second pass output ---\n%s",
+				second.FixedCount, first.Content, second.Content)
+		}
+	})
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
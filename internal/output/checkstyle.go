@@ -0,0 +1,84 @@
+// Project:   macbash
+// File:      internal/output/checkstyle.go
+// Purpose:   Emit scan results as Checkstyle XML for CI integrations
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package output
+
+import (
+	"encoding/xml"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+const checkstyleVersion = "4.3"
+
+type checkstyleLog struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// Checkstyle writes matches as Checkstyle XML, one <file> per distinct
+// Match.File (in first-seen order) and one <error> per match, so the
+// output can be consumed directly by Jenkins' Checkstyle plugin.
+func (f *Formatter) Checkstyle(matches []rules.Match) error {
+	log := checkstyleLog{Version: checkstyleVersion}
+
+	var order []string
+	errorsByFile := make(map[string][]checkstyleError)
+
+	for _, m := range matches {
+		if _, ok := errorsByFile[m.File]; !ok {
+			order = append(order, m.File)
+		}
+		errorsByFile[m.File] = append(errorsByFile[m.File], checkstyleError{
+			Line:     m.Line,
+			Column:   m.Column,
+			Severity: checkstyleSeverity(m.Rule.Severity),
+			Message:  m.Rule.Name,
+			Source:   m.Rule.ID,
+		})
+	}
+
+	for _, name := range order {
+		log.Files = append(log.Files, checkstyleFile{Name: name, Errors: errorsByFile[name]})
+	}
+
+	if _, err := f.writer.Write([]byte(xml.Header)); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(f.writer)
+	encoder.Indent("", "  ")
+	return encoder.Encode(log)
+}
+
+func checkstyleSeverity(s rules.Severity) string {
+	switch s {
+	case rules.SeverityError:
+		return "error"
+	case rules.SeverityWarning:
+		return "warning"
+	case rules.SeverityInfo:
+		return "info"
+	default:
+		return "warning"
+	}
+}
@@ -0,0 +1,93 @@
+// Project:   macbash
+// File:      internal/output/codeclimate.go
+// Purpose:   Emit scan results as Code Climate JSON for GitLab CI
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package output
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+type codeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Location    codeClimateLocation `json:"location"`
+	Fingerprint string              `json:"fingerprint"`
+}
+
+type codeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines codeClimateLines `json:"lines"`
+}
+
+type codeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// CodeClimate writes matches as a Code Climate issues JSON array, the
+// format GitLab CI's Code Quality widget expects.
+func (f *Formatter) CodeClimate(matches []rules.Match) error {
+	issues := make([]codeClimateIssue, len(matches))
+	for i, m := range matches {
+		issues[i] = codeClimateIssue{
+			Type:        "issue",
+			CheckName:   m.Rule.ID,
+			Description: m.Rule.Name,
+			Categories:  codeClimateCategories(m.Rule.Severity),
+			Severity:    codeClimateSeverity(m.Rule.Severity),
+			Location: codeClimateLocation{
+				Path:  m.File,
+				Lines: codeClimateLines{Begin: m.Line},
+			},
+			Fingerprint: codeClimateFingerprint(m),
+		}
+	}
+
+	encoder := json.NewEncoder(f.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
+
+func codeClimateCategories(s rules.Severity) []string {
+	switch s {
+	case rules.SeverityError:
+		return []string{"Bug Risk"}
+	case rules.SeverityInfo:
+		return []string{"Clarity"}
+	default:
+		return []string{"Style"}
+	}
+}
+
+func codeClimateSeverity(s rules.Severity) string {
+	switch s {
+	case rules.SeverityError:
+		return "critical"
+	case rules.SeverityInfo:
+		return "minor"
+	default:
+		return "major"
+	}
+}
+
+// codeClimateFingerprint derives a stable per-issue ID from the fields that
+// identify "the same issue" across runs - file, rule, line, and matched
+// text - so GitLab can track an issue's history between runs. Because the
+// line number is part of it, a finding that moves to another line gets a
+// new fingerprint.
+func codeClimateFingerprint(m rules.Match) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s%s%d%s", m.File, m.Rule.ID, m.Line, m.MatchedStr)))
+	return hex.EncodeToString(sum[:])
+}
@@ -15,6 +15,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/hypersec-io/macbash/internal/diag"
 	"github.com/hypersec-io/macbash/internal/fixer"
 	"github.com/hypersec-io/macbash/internal/rules"
 )
@@ -22,6 +23,7 @@ import (
 type Formatter struct {
 	writer    io.Writer
 	useColors bool
+	version   string
 }
 
 const (
@@ -46,6 +48,12 @@ func New(w io.Writer) *Formatter {
 	}
 }
 
+// SetVersion records the macbash build version to report as
+// tool.driver.version in SARIF output.
+func (f *Formatter) SetVersion(version string) {
+	f.version = version
+}
+
 func (f *Formatter) Text(matches []rules.Match) {
 	if len(matches) == 0 {
 		fmt.Fprintln(f.writer, f.color(colorBlue, "No issues found."))
@@ -177,6 +185,58 @@ func (f *Formatter) JSON(matches []rules.Match) error {
 	return encoder.Encode(output)
 }
 
+// Diagnostics prints the fixer's grouped diagnostics (findings plus, where
+// applicable, unified diff hunks) in the same text style used for autofix
+// previews by --show-autofix.
+func (f *Formatter) Diagnostics(diags []diag.Diagnostic) {
+	if len(diags) == 0 {
+		fmt.Fprintln(f.writer, f.color(colorBlue, "No issues found."))
+		return
+	}
+
+	fmt.Fprint(f.writer, diag.Format(diags))
+}
+
+// DiagnosticsJSON prints the fixer's diagnostics as a JSON array, one
+// object per diagnostic, including the fix (if any) as before/after line
+// slices rather than a raw diff.
+func (f *Formatter) DiagnosticsJSON(diags []diag.Diagnostic) error {
+	type jsonFix struct {
+		Before []string `json:"before"`
+		After  []string `json:"after"`
+	}
+
+	type jsonDiagnostic struct {
+		File      string   `json:"file"`
+		StartLine int      `json:"start_line"`
+		EndLine   int      `json:"end_line"`
+		Severity  string   `json:"severity"`
+		RuleID    string   `json:"rule_id"`
+		Message   string   `json:"message"`
+		Fix       *jsonFix `json:"fix,omitempty"`
+	}
+
+	out := make([]jsonDiagnostic, len(diags))
+	for i, d := range diags {
+		jd := jsonDiagnostic{
+			File:      d.File,
+			StartLine: d.StartLine,
+			EndLine:   d.EndLine,
+			Severity:  string(d.Severity),
+			RuleID:    d.RuleID,
+			Message:   d.Message,
+		}
+		if d.Fix != nil {
+			jd.Fix = &jsonFix{Before: d.Fix.Before, After: d.Fix.After}
+		}
+		out[i] = jd
+	}
+
+	encoder := json.NewEncoder(f.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
 func (f *Formatter) color(code, text string) string {
 	if !f.useColors {
 		return text
@@ -0,0 +1,254 @@
+// Project:   macbash
+// File:      internal/output/sarif.go
+// Purpose:   Emit scan results as SARIF 2.1.0 for code-scanning integrations
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package output
+
+import (
+	"encoding/json"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+const sarifVersion = "2.1.0"
+const sarifSchema = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json"
+
+// sarifSrcRootBaseID is the uriBaseId for every artifactLocation. Matches
+// are already relativized (see cli.relativizeMatchPaths) before reaching
+// the formatter, so this just tells SARIF consumers what that root is.
+const sarifSrcRootBaseID = "%SRCROOT%"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                     `json:"name"`
+	Version        string                     `json:"version,omitempty"`
+	InformationURI string                     `json:"informationUri,omitempty"`
+	Rules          []sarifReportingDescriptor `json:"rules"`
+}
+
+type sarifReportingDescriptor struct {
+	ID                   string                  `json:"id"`
+	Name                 string                  `json:"name"`
+	ShortDescription     sarifMultiformatMessage `json:"shortDescription,omitempty"`
+	FullDescription      sarifMultiformatMessage `json:"fullDescription,omitempty"`
+	HelpURI              string                  `json:"helpUri,omitempty"`
+	DefaultConfiguration sarifReportingConfig    `json:"defaultConfiguration"`
+}
+
+type sarifMultiformatMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifReportingConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+	Fixes     []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int                 `json:"startLine"`
+	StartColumn int                 `json:"startColumn,omitempty"`
+	EndColumn   int                 `json:"endColumn,omitempty"`
+	Snippet     *sarifRegionSnippet `json:"snippet,omitempty"`
+}
+
+type sarifRegionSnippet struct {
+	Text string `json:"text"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description,omitempty"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion          `json:"deletedRegion"`
+	InsertedContent sarifInsertedContent `json:"insertedContent"`
+}
+
+type sarifInsertedContent struct {
+	Text string `json:"text"`
+}
+
+// SARIF writes matches as a SARIF 2.1.0 log, one run with one tool driver.
+// Each distinct rules.Rule referenced by matches becomes a
+// reportingDescriptor; each Match becomes a result whose region spans
+// MatchedStr, and carries a fix replacing the whole line with FixedStr
+// when the rule has a FixTemplate and the match has a FixedStr.
+func (f *Formatter) SARIF(matches []rules.Match) error {
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:           "macbash",
+						Version:        f.version,
+						InformationURI: "https://github.com/hypersec-io/macbash",
+						Rules:          sarifRuleDescriptors(matches),
+					},
+				},
+				Results: make([]sarifResult, len(matches)),
+			},
+		},
+	}
+
+	for i, m := range matches {
+		log.Runs[0].Results[i] = sarifResultFor(&m)
+	}
+
+	encoder := json.NewEncoder(f.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+func sarifRuleDescriptors(matches []rules.Match) []sarifReportingDescriptor {
+	seen := make(map[string]bool)
+	var descriptors []sarifReportingDescriptor
+
+	for _, m := range matches {
+		if seen[m.Rule.ID] {
+			continue
+		}
+		seen[m.Rule.ID] = true
+		descriptors = append(descriptors, sarifRuleDescriptor(m.Rule))
+	}
+
+	return descriptors
+}
+
+func sarifRuleDescriptor(r *rules.Rule) sarifReportingDescriptor {
+	d := sarifReportingDescriptor{
+		ID:   r.ID,
+		Name: r.Name,
+		ShortDescription: sarifMultiformatMessage{
+			Text: r.Name,
+		},
+		FullDescription: sarifMultiformatMessage{
+			Text: r.Description,
+		},
+		DefaultConfiguration: sarifReportingConfig{
+			Level: sarifLevel(r.Severity),
+		},
+	}
+
+	if len(r.References) > 0 {
+		d.HelpURI = r.References[0]
+	}
+
+	return d
+}
+
+func sarifResultFor(m *rules.Match) sarifResult {
+	endColumn := m.Column + len(m.MatchedStr)
+	artifact := sarifArtifactLocation{URI: m.File, URIBaseID: sarifSrcRootBaseID}
+
+	result := sarifResult{
+		RuleID: m.Rule.ID,
+		Level:  sarifLevel(m.Rule.Severity),
+		Message: sarifMessage{
+			Text: m.Rule.Name,
+		},
+		Locations: []sarifLocation{
+			{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: artifact,
+					Region: sarifRegion{
+						StartLine:   m.Line,
+						StartColumn: m.Column,
+						EndColumn:   endColumn,
+						Snippet:     &sarifRegionSnippet{Text: m.Content},
+					},
+				},
+			},
+		},
+	}
+
+	if m.Rule.FixTemplate != "" && m.FixedStr != "" {
+		// FixedStr is the whole fixed line, so the replacement deletes the
+		// whole original line rather than just the MatchedStr span.
+		result.Fixes = []sarifFix{
+			{
+				Description: sarifMessage{Text: m.Rule.Name},
+				ArtifactChanges: []sarifArtifactChange{
+					{
+						ArtifactLocation: artifact,
+						Replacements: []sarifReplacement{
+							{
+								DeletedRegion: sarifRegion{
+									StartLine:   m.Line,
+									StartColumn: 1,
+									EndColumn:   len(m.Content) + 1,
+								},
+								InsertedContent: sarifInsertedContent{Text: m.FixedStr},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return result
+}
+
+func sarifLevel(s rules.Severity) string {
+	switch s {
+	case rules.SeverityError:
+		return "error"
+	case rules.SeverityWarning:
+		return "warning"
+	case rules.SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
@@ -11,8 +11,12 @@ package rules
 import (
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -20,13 +24,156 @@ import (
 //go:embed builtin/*.yaml
 var builtinRules embed.FS
 
+// LoadFromFile loads a ruleset from path, which may be a single YAML file
+// or a directory walked recursively for *.yaml/*.yml files. Any `includes:`
+// entries are resolved relative to the file that declares them (unless
+// absolute) and merged in alongside it, respecting each ruleset's
+// `priority:`. Include cycles are rejected rather than looping forever.
 func LoadFromFile(path string) (*RuleSet, error) {
-	data, err := os.ReadFile(path)
+	return loadPath(path, make(map[string]bool))
+}
+
+// LoadLayered loads each of paths independently with LoadFromFile and
+// merges the results in priority order, so e.g. a --rules-dir directory
+// can be layered on top of built-ins without forking them.
+func LoadLayered(paths ...string) (*RuleSet, error) {
+	sets := make([]*RuleSet, 0, len(paths))
+	for _, p := range paths {
+		rs, err := LoadFromFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("loading rules from %s: %w", p, err)
+		}
+		sets = append(sets, rs)
+	}
+	return mergeByPriority(sets), nil
+}
+
+func loadPath(path string, visiting map[string]bool) (*RuleSet, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rules path %s: %w", path, err)
+	}
+
+	if info.IsDir() {
+		return loadDir(path, visiting)
+	}
+
+	return loadFile(path, visiting)
+}
+
+func loadDir(dir string, visiting map[string]bool) (*RuleSet, error) {
+	var sets []*RuleSet
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(p)) {
+		case ".yaml", ".yml":
+		default:
+			return nil
+		}
+
+		rs, err := loadFile(p, visiting)
+		if err != nil {
+			return err
+		}
+		sets = append(sets, rs)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking rules directory %s: %w", dir, err)
+	}
+
+	return mergeByPriority(sets), nil
+}
+
+func loadFile(path string, visiting map[string]bool) (*RuleSet, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path %s: %w", path, err)
+	}
+
+	if visiting[abs] {
+		return nil, fmt.Errorf("include cycle detected at %s", path)
+	}
+	visiting[abs] = true
+	defer delete(visiting, abs)
+
+	data, err := os.ReadFile(abs)
 	if err != nil {
 		return nil, fmt.Errorf("reading rules file %s: %w", path, err)
 	}
 
-	return parseRuleSet(data, path)
+	rs, err := parseRuleSet(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	baseDir := filepath.Dir(abs)
+	if err := resolveRegoFiles(rs, baseDir); err != nil {
+		return nil, err
+	}
+
+	if len(rs.Includes) == 0 {
+		return rs, nil
+	}
+
+	sets := []*RuleSet{rs}
+	for _, inc := range rs.Includes {
+		incPath := inc
+		if !filepath.IsAbs(incPath) {
+			incPath = filepath.Join(baseDir, incPath)
+		}
+
+		included, err := loadPath(incPath, visiting)
+		if err != nil {
+			return nil, fmt.Errorf("including %s from %s: %w", inc, path, err)
+		}
+		sets = append(sets, included)
+	}
+
+	return mergeByPriority(sets), nil
+}
+
+// resolveRegoFiles reads each rego rule's RegoFile (resolved relative to
+// baseDir, unless absolute) into Rego, so everything downstream of loading
+// only ever has to deal with a policy body, not a path. Rules that set
+// Rego directly are left untouched.
+func resolveRegoFiles(rs *RuleSet, baseDir string) error {
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Type != RuleTypeRego || r.RegoFile == "" {
+			continue
+		}
+
+		path := r.RegoFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading rego_file for rule %s: %w", r.ID, err)
+		}
+		r.Rego = string(data)
+	}
+	return nil
+}
+
+// mergeByPriority merges rulesets in ascending priority order, so a higher
+// `priority:` value overrides a lower one on conflicting rule IDs. Equal
+// priorities (the default) keep their original, stable order.
+func mergeByPriority(sets []*RuleSet) *RuleSet {
+	ordered := make([]*RuleSet, len(sets))
+	copy(ordered, sets)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Priority < ordered[j].Priority
+	})
+	return Merge(ordered...)
 }
 
 func LoadBuiltin() (*RuleSet, error) {
@@ -74,6 +221,10 @@ func parseRuleSet(data []byte, source string) (*RuleSet, error) {
 		}
 	}
 
+	if err := validateSeverityPolicy(rs.Severity); err != nil {
+		return nil, fmt.Errorf("invalid severity policy in %s: %w", source, err)
+	}
+
 	return &rs, nil
 }
 
@@ -86,13 +237,24 @@ func validateRule(r *Rule) error {
 		return fmt.Errorf("rule %s missing name", r.ID)
 	}
 
-	if r.Pattern == "" {
-		return fmt.Errorf("rule %s missing pattern", r.ID)
+	if r.Type == RuleTypeRego {
+		if r.Rego == "" && r.RegoFile == "" {
+			return fmt.Errorf("rule %s has type rego but no rego or rego_file", r.ID)
+		}
+	} else if r.Pattern == "" && r.ASTMatch == nil {
+		return fmt.Errorf("rule %s missing pattern or ast_match", r.ID)
+	}
+
+	// Validate pattern compiles, if present. Rules backed purely by an
+	// ast_match block have no regex to compile.
+	if r.Pattern != "" {
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("rule %s has invalid pattern: %w", r.ID, err)
+		}
 	}
 
-	// Validate pattern compiles
-	if _, err := regexp.Compile(r.Pattern); err != nil {
-		return fmt.Errorf("rule %s has invalid pattern: %w", r.ID, err)
+	if r.ASTMatch != nil && r.ASTMatch.Kind == "" {
+		return fmt.Errorf("rule %s has ast_match with no kind", r.ID)
 	}
 
 	// Validate negative pattern if present
@@ -154,6 +316,10 @@ func Merge(sets ...*RuleSet) *RuleSet {
 				combined.Rules = append(combined.Rules, *rule)
 			}
 		}
+		if rs.Severity != nil {
+			// Later sets override earlier ones, same as rule IDs above.
+			combined.Severity = rs.Severity
+		}
 	}
 
 	return combined
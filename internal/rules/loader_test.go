@@ -11,6 +11,7 @@ package rules
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -245,3 +246,202 @@ func TestMerge_NilSets(t *testing.T) {
 		t.Errorf("expected 1 rule, got %d", len(merged.Rules))
 	}
 }
+
+func TestLoadFromFile_Directory(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRulesFile(t, filepath.Join(dir, "a.yaml"), `version: "1.0"
+rules:
+  - id: rule-a
+    name: Rule A
+    pattern: 'a'
+`)
+	writeRulesFile(t, filepath.Join(dir, "nested", "b.yaml"), `version: "1.0"
+rules:
+  - id: rule-b
+    name: Rule B
+    pattern: 'b'
+`)
+
+	rs, err := LoadFromFile(dir)
+	if err != nil {
+		t.Fatalf("LoadFromFile(%s) error = %v", dir, err)
+	}
+
+	if len(rs.Rules) != 2 {
+		t.Fatalf("expected 2 rules from directory walk, got %d", len(rs.Rules))
+	}
+}
+
+func TestLoadFromFile_Includes(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRulesFile(t, filepath.Join(dir, "base.yaml"), `version: "1.0"
+includes: ["extra.yaml"]
+rules:
+  - id: base-rule
+    name: Base Rule
+    pattern: 'base'
+`)
+	writeRulesFile(t, filepath.Join(dir, "extra.yaml"), `version: "1.0"
+rules:
+  - id: extra-rule
+    name: Extra Rule
+    pattern: 'extra'
+`)
+
+	rs, err := LoadFromFile(filepath.Join(dir, "base.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	ids := make(map[string]bool)
+	for _, r := range rs.Rules {
+		ids[r.ID] = true
+	}
+	if !ids["base-rule"] || !ids["extra-rule"] {
+		t.Errorf("expected both base-rule and extra-rule, got %v", rs.Rules)
+	}
+}
+
+func TestLoadFromFile_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRulesFile(t, filepath.Join(dir, "a.yaml"), `version: "1.0"
+includes: ["b.yaml"]
+rules:
+  - id: rule-a
+    name: Rule A
+    pattern: 'a'
+`)
+	writeRulesFile(t, filepath.Join(dir, "b.yaml"), `version: "1.0"
+includes: ["a.yaml"]
+rules:
+  - id: rule-b
+    name: Rule B
+    pattern: 'b'
+`)
+
+	_, err := LoadFromFile(filepath.Join(dir, "a.yaml"))
+	if err == nil {
+		t.Error("LoadFromFile() should fail on an include cycle")
+	}
+}
+
+func TestLoadLayered_Priority(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRulesFile(t, filepath.Join(dir, "low.yaml"), `version: "1.0"
+priority: 0
+rules:
+  - id: shared-rule
+    name: Low Priority
+    pattern: 'low'
+`)
+	writeRulesFile(t, filepath.Join(dir, "high.yaml"), `version: "1.0"
+priority: 10
+rules:
+  - id: shared-rule
+    name: High Priority
+    pattern: 'high'
+`)
+
+	// Pass the higher-priority file first; priority, not argument order,
+	// must decide which one wins.
+	rs, err := LoadLayered(filepath.Join(dir, "high.yaml"), filepath.Join(dir, "low.yaml"))
+	if err != nil {
+		t.Fatalf("LoadLayered() error = %v", err)
+	}
+
+	if len(rs.Rules) != 1 {
+		t.Fatalf("expected 1 rule after merge, got %d", len(rs.Rules))
+	}
+	if rs.Rules[0].Name != "High Priority" {
+		t.Errorf("expected higher-priority ruleset to win, got %q", rs.Rules[0].Name)
+	}
+}
+
+func TestValidateRule_Rego(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    Rule
+		wantErr bool
+	}{
+		{
+			name: "rego rule with inline policy",
+			rule: Rule{
+				ID:   "rego-test",
+				Name: "Rego Test",
+				Type: RuleTypeRego,
+				Rego: "package macbash\n\nviolations[v] { v := {\"line\": 1} }\n",
+			},
+			wantErr: false,
+		},
+		{
+			name: "rego rule with rego_file",
+			rule: Rule{
+				ID:       "rego-test",
+				Name:     "Rego Test",
+				Type:     RuleTypeRego,
+				RegoFile: "policy.rego",
+			},
+			wantErr: false,
+		},
+		{
+			name: "rego rule missing both rego and rego_file",
+			rule: Rule{
+				ID:   "rego-test",
+				Name: "Rego Test",
+				Type: RuleTypeRego,
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRule(&tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadFromFile_RegoFile(t *testing.T) {
+	dir := t.TempDir()
+
+	writeRulesFile(t, filepath.Join(dir, "policy.rego"), `package macbash
+
+violations[v] { v := {"line": 1, "matched": "sed -i"} }
+`)
+	writeRulesFile(t, filepath.Join(dir, "rules.yaml"), `version: "1.0"
+rules:
+  - id: rego-sed-inplace
+    name: sed -i without backup (rego)
+    type: rego
+    rego_file: policy.rego
+`)
+
+	rs, err := LoadFromFile(filepath.Join(dir, "rules.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	if len(rs.Rules) != 1 {
+		t.Fatalf("expected 1 rule, got %d", len(rs.Rules))
+	}
+	if !strings.Contains(rs.Rules[0].Rego, "violations[v]") {
+		t.Errorf("expected rego_file contents loaded into Rego, got %q", rs.Rules[0].Rego)
+	}
+}
+
+func writeRulesFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
@@ -0,0 +1,114 @@
+// Project:   macbash
+// File:      internal/rules/policy.go
+// Purpose:   Per-path severity overrides and rule enable/disable
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package rules
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ApplyPolicy rewrites matches according to rs.Severity: a match whose
+// file and rule ID hit a PathSeverityRule with Disable set is dropped;
+// otherwise its severity is overridden by that rule's Severity, or by
+// DefaultSeverity if no rule matched. The base Rule is never mutated - an
+// override installs a shallow copy of it on the returned Match instead.
+//
+// Invalid Path regexes are skipped rather than erroring, since LoadFromFile
+// already rejects them at parse time via validateSeverityPolicy.
+func (rs *RuleSet) ApplyPolicy(path string, matches []Match) []Match {
+	if rs == nil || rs.Severity == nil || len(matches) == 0 {
+		return matches
+	}
+	policy := rs.Severity
+
+	out := make([]Match, 0, len(matches))
+	for _, m := range matches {
+		severity, disabled := policy.resolve(path, m.Rule.ID)
+		if disabled {
+			continue
+		}
+		if severity != "" {
+			ruleCopy := *m.Rule
+			ruleCopy.Severity = severity
+			m.Rule = &ruleCopy
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// resolve returns the overridden severity (if any) and whether ruleID is
+// disabled for path, checking p.Rules in order and falling back to
+// DefaultSeverity when none match.
+func (p *SeverityPolicy) resolve(path, ruleID string) (Severity, bool) {
+	for _, pr := range p.Rules {
+		re, err := regexp.Compile(pr.Path)
+		if err != nil || !re.MatchString(path) {
+			continue
+		}
+		if !pr.RuleIDs.Matches(ruleID) {
+			continue
+		}
+		if pr.Disable {
+			return "", true
+		}
+		return pr.Severity, false
+	}
+
+	if p.DefaultSeverity != "" {
+		return Severity(p.DefaultSeverity), false
+	}
+	return "", false
+}
+
+// validateSeverityPolicy checks that p's path regexes compile and that
+// every rule entry sets a severity unless it disables the rule outright.
+func validateSeverityPolicy(p *SeverityPolicy) error {
+	if p == nil {
+		return nil
+	}
+
+	if p.DefaultSeverity != "" {
+		if err := validateSeverityValue(Severity(p.DefaultSeverity)); err != nil {
+			return fmt.Errorf("default_severity: %w", err)
+		}
+	}
+
+	for i, pr := range p.Rules {
+		if pr.Path == "" {
+			return fmt.Errorf("rules[%d] missing path", i)
+		}
+		if _, err := regexp.Compile(pr.Path); err != nil {
+			return fmt.Errorf("rules[%d] has invalid path: %w", i, err)
+		}
+		if len(pr.RuleIDs) == 0 {
+			return fmt.Errorf("rules[%d] missing rule_ids", i)
+		}
+		if pr.Disable {
+			continue
+		}
+		if pr.Severity == "" {
+			return fmt.Errorf("rules[%d] must set severity or disable", i)
+		}
+		if err := validateSeverityValue(pr.Severity); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func validateSeverityValue(s Severity) error {
+	switch s {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return nil
+	default:
+		return fmt.Errorf("invalid severity %q", s)
+	}
+}
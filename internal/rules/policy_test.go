@@ -0,0 +1,143 @@
+// Project:   macbash
+// File:      internal/rules/policy_test.go
+// Purpose:   Tests for per-path severity overrides and rule disabling
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package rules
+
+import "testing"
+
+func TestRuleSet_ApplyPolicy(t *testing.T) {
+	rule := Rule{ID: "grep-perl-regex", Name: "Grep Perl Regex", Severity: SeverityError}
+	rs := &RuleSet{
+		Rules: []Rule{rule},
+		Severity: &SeverityPolicy{
+			DefaultSeverity: "info",
+			Rules: []PathSeverityRule{
+				{Path: `^tests/`, RuleIDs: RuleIDList{"*"}, Severity: SeverityInfo},
+				{Path: `^bin/`, RuleIDs: RuleIDList{"grep-perl-regex"}, Disable: true},
+			},
+		},
+	}
+
+	m := Match{Rule: &rs.Rules[0]}
+
+	t.Run("path override demotes severity", func(t *testing.T) {
+		out := rs.ApplyPolicy("tests/foo.sh", []Match{m})
+		if len(out) != 1 {
+			t.Fatalf("expected 1 match, got %d", len(out))
+		}
+		if out[0].Rule.Severity != SeverityInfo {
+			t.Errorf("expected severity info, got %q", out[0].Rule.Severity)
+		}
+		if rs.Rules[0].Severity != SeverityError {
+			t.Errorf("base rule must not be mutated, got %q", rs.Rules[0].Severity)
+		}
+	})
+
+	t.Run("path override disables the match", func(t *testing.T) {
+		out := rs.ApplyPolicy("bin/foo.sh", []Match{m})
+		if len(out) != 0 {
+			t.Fatalf("expected match to be dropped, got %d", len(out))
+		}
+	})
+
+	t.Run("default_severity applies when nothing else matches", func(t *testing.T) {
+		out := rs.ApplyPolicy("lib/foo.sh", []Match{m})
+		if len(out) != 1 || out[0].Rule.Severity != SeverityInfo {
+			t.Fatalf("expected default_severity fallback, got %+v", out)
+		}
+	})
+
+	t.Run("no policy is a no-op", func(t *testing.T) {
+		plain := &RuleSet{Rules: []Rule{rule}}
+		out := plain.ApplyPolicy("tests/foo.sh", []Match{m})
+		if len(out) != 1 || out[0].Rule.Severity != SeverityError {
+			t.Fatalf("expected match unchanged, got %+v", out)
+		}
+	})
+}
+
+func TestRuleIDList_Matches(t *testing.T) {
+	wildcard := RuleIDList{"*"}
+	if !wildcard.Matches("anything") {
+		t.Error("expected wildcard to match any rule ID")
+	}
+
+	explicit := RuleIDList{"rule-a", "rule-b"}
+	if !explicit.Matches("rule-a") {
+		t.Error("expected explicit list to match a listed rule ID")
+	}
+	if explicit.Matches("rule-c") {
+		t.Error("expected explicit list to not match an unlisted rule ID")
+	}
+}
+
+func TestValidateSeverityPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *SeverityPolicy
+		wantErr bool
+	}{
+		{name: "nil policy", policy: nil, wantErr: false},
+		{
+			name: "valid override",
+			policy: &SeverityPolicy{
+				Rules: []PathSeverityRule{{Path: "tests/.*", RuleIDs: RuleIDList{"*"}, Severity: SeverityInfo}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid disable needs no severity",
+			policy: &SeverityPolicy{
+				Rules: []PathSeverityRule{{Path: "bin/.*", RuleIDs: RuleIDList{"*"}, Disable: true}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing path",
+			policy: &SeverityPolicy{
+				Rules: []PathSeverityRule{{RuleIDs: RuleIDList{"*"}, Severity: SeverityInfo}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid path regex",
+			policy: &SeverityPolicy{
+				Rules: []PathSeverityRule{{Path: "[invalid", RuleIDs: RuleIDList{"*"}, Severity: SeverityInfo}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing rule_ids",
+			policy: &SeverityPolicy{
+				Rules: []PathSeverityRule{{Path: "tests/.*", Severity: SeverityInfo}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "override without severity or disable",
+			policy: &SeverityPolicy{
+				Rules: []PathSeverityRule{{Path: "tests/.*", RuleIDs: RuleIDList{"*"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "invalid default_severity",
+			policy:  &SeverityPolicy{DefaultSeverity: "bogus"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSeverityPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateSeverityPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
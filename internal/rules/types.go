@@ -8,6 +8,8 @@
 
 package rules
 
+import "gopkg.in/yaml.v3"
+
 type Severity string
 
 const (
@@ -25,21 +27,53 @@ const (
 	FixTransform FixType = "transform"
 )
 
+// RuleTypeRego marks a Rule whose Type is "rego": it ships a Rego policy
+// (Rego inline, or RegoFile loaded at parse time) evaluated by the rego
+// scanner instead of a Pattern or ASTMatch predicate.
+const RuleTypeRego = "rego"
+
 type Rule struct {
-	ID              string   `yaml:"id"`
-	Name            string   `yaml:"name"`
-	Description     string   `yaml:"description"`
-	Severity        Severity `yaml:"severity"`
-	Pattern         string   `yaml:"pattern"`
-	NegativePattern string   `yaml:"negative_pattern,omitempty"`
-	ShebangMatch    string   `yaml:"shebang_match,omitempty"`
-	FixType         FixType  `yaml:"fix_type"`
-	FixTemplate     string   `yaml:"fix_template,omitempty"`
-	WhyUnfixable    string   `yaml:"why_unfixable,omitempty"`
-	FixFunction     string   `yaml:"fix_function,omitempty"`
-	Examples        Examples `yaml:"examples,omitempty"`
-	Tags            []string `yaml:"tags,omitempty"`
-	References      []string `yaml:"references,omitempty"`
+	ID              string    `yaml:"id"`
+	Name            string    `yaml:"name"`
+	Description     string    `yaml:"description"`
+	Severity        Severity  `yaml:"severity"`
+	Pattern         string    `yaml:"pattern"`
+	NegativePattern string    `yaml:"negative_pattern,omitempty"`
+	ShebangMatch    string    `yaml:"shebang_match,omitempty"`
+	ASTMatch        *ASTMatch `yaml:"ast_match,omitempty"`
+
+	// Type selects the rule's matching engine. Empty (the default)
+	// evaluates Pattern or ASTMatch as above; RuleTypeRego evaluates Rego
+	// (or RegoFile, resolved into Rego at load time) instead.
+	Type     string `yaml:"type,omitempty"`
+	Rego     string `yaml:"rego,omitempty"`
+	RegoFile string `yaml:"rego_file,omitempty"`
+
+	FixType      FixType  `yaml:"fix_type"`
+	FixTemplate  string   `yaml:"fix_template,omitempty"`
+	WhyUnfixable string   `yaml:"why_unfixable,omitempty"`
+	FixFunction  string   `yaml:"fix_function,omitempty"`
+	Examples     Examples `yaml:"examples,omitempty"`
+	Tags         []string `yaml:"tags,omitempty"`
+	References   []string `yaml:"references,omitempty"`
+}
+
+// ASTMatch describes a structural predicate evaluated against a node in the
+// mvdan.cc/sh/v3 syntax tree, as an alternative to a line-oriented Pattern.
+// Kind names a syntax.Node type (e.g. "CallExpr", "ParamExp", "TestClause",
+// "DeclClause", "Stmt"), or "Pipeline", which matches a BinaryCmd joined by
+// | or |&; the remaining fields narrow the match within that kind.
+type ASTMatch struct {
+	Kind     string `yaml:"kind"`
+	Command  string `yaml:"command,omitempty"`
+	Flag     string `yaml:"flag,omitempty"`
+	Operator string `yaml:"operator,omitempty"`
+
+	// Negated requires the statement to carry a leading "!" (kind: Stmt
+	// only). For Command, "[[" is a sentinel meaning the negated command
+	// must be a bash TestClause ("! [[ ... ]]") rather than a CallExpr
+	// named "test" or "[".
+	Negated bool `yaml:"negated,omitempty"`
 }
 
 type Examples struct {
@@ -49,7 +83,77 @@ type Examples struct {
 
 type RuleSet struct {
 	Version string `yaml:"version"`
-	Rules   []Rule `yaml:"rules"`
+
+	// Priority orders this ruleset relative to others when several are
+	// merged together (lower loads first, higher overrides on conflicting
+	// rule IDs). Rulesets with equal priority keep their discovery order.
+	Priority int `yaml:"priority,omitempty"`
+
+	// Includes names other rule files or directories (resolved relative
+	// to this file, unless absolute) to merge in alongside this
+	// ruleset's own Rules.
+	Includes []string `yaml:"includes,omitempty"`
+
+	// Severity configures per-path severity overrides and rule
+	// enable/disable, applied via ApplyPolicy after scanning.
+	Severity *SeverityPolicy `yaml:"severity,omitempty"`
+
+	Rules []Rule `yaml:"rules"`
+}
+
+// SeverityPolicy is modeled on golangci-lint's severity rules:
+// DefaultSeverity is the fallback applied to any match that no Rules entry
+// overrides; Rules are evaluated in order and the first entry whose Path
+// and RuleIDs both match wins.
+type SeverityPolicy struct {
+	DefaultSeverity string             `yaml:"default_severity,omitempty"`
+	Rules           []PathSeverityRule `yaml:"rules,omitempty"`
+}
+
+// PathSeverityRule overrides the severity of (or disables) RuleIDs for
+// files whose path matches the Path regex.
+type PathSeverityRule struct {
+	Path     string     `yaml:"path"`
+	RuleIDs  RuleIDList `yaml:"rule_ids"`
+	Severity Severity   `yaml:"severity,omitempty"`
+	Disable  bool       `yaml:"disable,omitempty"`
+}
+
+// RuleIDList is a YAML rule_ids value: either the literal string "*"
+// (matching every rule ID) or an explicit list of rule IDs. UnmarshalYAML
+// accepts both forms so rule_ids: "*" doesn't have to be written as a
+// single-element list.
+type RuleIDList []string
+
+const ruleIDWildcard = "*"
+
+func (r *RuleIDList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var s string
+		if err := value.Decode(&s); err != nil {
+			return err
+		}
+		*r = RuleIDList{s}
+		return nil
+	}
+
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*r = RuleIDList(list)
+	return nil
+}
+
+// Matches reports whether id is covered by r, either directly or via the
+// "*" wildcard.
+func (r RuleIDList) Matches(id string) bool {
+	for _, x := range r {
+		if x == ruleIDWildcard || x == id {
+			return true
+		}
+	}
+	return false
 }
 
 type Match struct {
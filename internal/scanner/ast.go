@@ -0,0 +1,329 @@
+// Project:   macbash
+// File:      internal/scanner/ast.go
+// Purpose:   AST-backed scanning using mvdan.cc/sh/v3 syntax trees
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package scanner
+
+import (
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+// caseOps maps the operator spelling used in ast_match.operator (as it would
+// appear inside ${var...}) to the syntax package's case-conversion operator.
+var caseOps = map[string]syntax.ParExpOperator{
+	"^^": syntax.UpperAll,
+	"^":  syntax.UpperFirst,
+	",,": syntax.LowerAll,
+	",":  syntax.LowerFirst,
+}
+
+// astScanner walks a parsed syntax tree once per file and dispatches to
+// node-shaped predicates for rules that declare an ast_match block, rather
+// than re-running a regex over every line. Pipeline rules are split out
+// from the rest: they need a file-wide pass (is pipefail already on?)
+// before any per-pipeline decision, so they can't be decided node-by-node
+// like the others.
+type astScanner struct {
+	nodeRules     []*rules.Rule
+	pipelineRules []*rules.Rule
+}
+
+func newASTScanner(astRules []*rules.Rule) *astScanner {
+	a := &astScanner{}
+	for _, r := range astRules {
+		if r.ASTMatch != nil && r.ASTMatch.Kind == "Pipeline" {
+			a.pipelineRules = append(a.pipelineRules, r)
+			continue
+		}
+		a.nodeRules = append(a.nodeRules, r)
+	}
+	return a
+}
+
+func (a *astScanner) scan(path, content string) ([]rules.Match, error) {
+	if len(a.nodeRules) == 0 && len(a.pipelineRules) == 0 {
+		return nil, nil
+	}
+
+	parser := syntax.NewParser(syntax.Variant(syntax.LangBash), syntax.KeepComments(true))
+	file, err := parser.Parse(strings.NewReader(content), path)
+	if err != nil {
+		// Syntax errors are reported by the fixer's validation path, not
+		// the scanner; AST rules simply find nothing to walk.
+		return nil, nil
+	}
+
+	lines := strings.Split(content, "\n")
+	var matches []rules.Match
+
+	if len(a.nodeRules) > 0 {
+		syntax.Walk(file, func(node syntax.Node) bool {
+			if node == nil {
+				return true
+			}
+			for _, r := range a.nodeRules {
+				if m, ok := matchNode(r, node, path, lines); ok {
+					matches = append(matches, m)
+				}
+			}
+			return true
+		})
+	}
+
+	if len(a.pipelineRules) > 0 {
+		hasPipefail := fileHasPipefail(file)
+		for _, r := range a.pipelineRules {
+			matches = append(matches, scanPipelines(r, file, path, lines, hasPipefail)...)
+		}
+	}
+
+	return matches, nil
+}
+
+func matchNode(r *rules.Rule, node syntax.Node, path string, lines []string) (rules.Match, bool) {
+	am := r.ASTMatch
+
+	switch am.Kind {
+	case "CallExpr":
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return rules.Match{}, false
+		}
+		return matchCallExpr(r, call, path, lines)
+
+	case "ParamExp":
+		pe, ok := node.(*syntax.ParamExp)
+		if !ok {
+			return rules.Match{}, false
+		}
+		return matchParamExp(r, pe, path, lines)
+
+	case "TestClause":
+		tc, ok := node.(*syntax.TestClause)
+		if !ok {
+			return rules.Match{}, false
+		}
+		return newNodeMatch(r, tc, path, lines), true
+
+	case "DeclClause":
+		dc, ok := node.(*syntax.DeclClause)
+		if !ok {
+			return rules.Match{}, false
+		}
+		if am.Command != "" && dc.Variant.Value != am.Command {
+			return rules.Match{}, false
+		}
+		return newNodeMatch(r, dc, path, lines), true
+
+	case "Stmt":
+		st, ok := node.(*syntax.Stmt)
+		if !ok {
+			return rules.Match{}, false
+		}
+		return matchNegatedStmt(r, st, path, lines)
+	}
+
+	return rules.Match{}, false
+}
+
+// matchNegatedStmt matches a leading "!" in front of a test idiom: `! test
+// ...`, `! [ ... ]`, or `! [[ ... ]]`. am.Command selects which form - "test"
+// or "[" narrow to the CallExpr of that name, while the "[[" sentinel
+// matches a bash TestClause instead, since that form has no Command word to
+// compare against.
+func matchNegatedStmt(r *rules.Rule, st *syntax.Stmt, path string, lines []string) (rules.Match, bool) {
+	am := r.ASTMatch
+	if am.Negated && !st.Negated {
+		return rules.Match{}, false
+	}
+
+	switch cmd := st.Cmd.(type) {
+	case *syntax.CallExpr:
+		if len(cmd.Args) == 0 || am.Command == "" || am.Command == "[[" {
+			return rules.Match{}, false
+		}
+		if wordLiteral(cmd.Args[0]) != am.Command {
+			return rules.Match{}, false
+		}
+	case *syntax.TestClause:
+		if am.Command != "[[" {
+			return rules.Match{}, false
+		}
+	default:
+		return rules.Match{}, false
+	}
+
+	return newNodeMatch(r, st, path, lines), true
+}
+
+// riskyPipeHeads are commands whose failure produces no output (rather than
+// a nonzero exit from the pipe's last stage) - or, for curl/wget, whose
+// output is about to be executed as code. riskyPipeConsumers are the
+// right-hand commands that swallow that failure silently.
+var (
+	riskyPipeHeads     = map[string]bool{"grep": true, "curl": true, "wget": true, "find": true, "cat": true}
+	riskyPipeConsumers = map[string]bool{"sh": true, "bash": true, "zsh": true}
+)
+
+// fileHasPipefail reports whether the file contains a `set` call whose
+// arguments mention pipefail, in either the combined (`set -euo pipefail`)
+// or split (`set -o pipefail`) form - enough to make `$?` after a pipeline
+// reflect a failing left-hand stage rather than masking it.
+func fileHasPipefail(file *syntax.File) bool {
+	has := false
+	syntax.Walk(file, func(node syntax.Node) bool {
+		if has {
+			return false
+		}
+		call, ok := node.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 || wordLiteral(call.Args[0]) != "set" {
+			return true
+		}
+		for _, arg := range call.Args[1:] {
+			if strings.Contains(wordLiteral(arg), "pipefail") {
+				has = true
+				break
+			}
+		}
+		return true
+	})
+	return has
+}
+
+// scanPipelines walks the file for pipelines whose left-hand side can fail
+// without output (grep, curl, ...) feeding a consumer that discards the
+// exit status (a while loop, or a shell invoked on piped-in content), and
+// reports one match per such pipeline - unless the file already turns
+// pipefail on, in which case $? already reports the failure correctly.
+func scanPipelines(rule *rules.Rule, file *syntax.File, path string, lines []string, hasPipefail bool) []rules.Match {
+	if hasPipefail {
+		return nil
+	}
+
+	var matches []rules.Match
+	syntax.Walk(file, func(node syntax.Node) bool {
+		bc, ok := node.(*syntax.BinaryCmd)
+		if !ok || (bc.Op != syntax.Pipe && bc.Op != syntax.PipeAll) {
+			return true
+		}
+		if isSilentFailurePipe(bc) {
+			matches = append(matches, newNodeMatch(rule, bc, path, lines))
+		}
+		return true
+	})
+	return matches
+}
+
+func isSilentFailurePipe(bc *syntax.BinaryCmd) bool {
+	left, ok := bc.X.Cmd.(*syntax.CallExpr)
+	if !ok || len(left.Args) == 0 || !riskyPipeHeads[wordLiteral(left.Args[0])] {
+		return false
+	}
+
+	switch right := bc.Y.Cmd.(type) {
+	case *syntax.WhileClause:
+		return true
+	case *syntax.CallExpr:
+		return len(right.Args) > 0 && riskyPipeConsumers[wordLiteral(right.Args[0])]
+	default:
+		return false
+	}
+}
+
+func matchCallExpr(r *rules.Rule, call *syntax.CallExpr, path string, lines []string) (rules.Match, bool) {
+	name := wordLiteral(call.Args[0])
+	if r.ASTMatch.Command != "" && name != r.ASTMatch.Command {
+		return rules.Match{}, false
+	}
+
+	if r.ASTMatch.Flag != "" {
+		found := false
+		for _, arg := range call.Args[1:] {
+			if strings.Contains(wordLiteral(arg), r.ASTMatch.Flag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return rules.Match{}, false
+		}
+	}
+
+	return newNodeMatch(r, call, path, lines), true
+}
+
+func matchParamExp(r *rules.Rule, pe *syntax.ParamExp, path string, lines []string) (rules.Match, bool) {
+	if r.ASTMatch.Operator == "" || pe.Exp == nil {
+		return rules.Match{}, false
+	}
+
+	wantOp, known := caseOps[r.ASTMatch.Operator]
+	if !known || pe.Exp.Op != wantOp {
+		return rules.Match{}, false
+	}
+
+	return newNodeMatch(r, pe, path, lines), true
+}
+
+func newNodeMatch(r *rules.Rule, node syntax.Node, path string, lines []string) rules.Match {
+	pos := node.Pos()
+	lineNum := int(pos.Line())
+
+	content := ""
+	if idx := lineNum - 1; idx >= 0 && idx < len(lines) {
+		content = lines[idx]
+	}
+
+	return rules.Match{
+		Rule:       r,
+		File:       path,
+		Line:       lineNum,
+		Column:     int(pos.Col()),
+		Content:    content,
+		MatchedStr: nodeSource(node, lines),
+	}
+}
+
+// wordLiteral renders a *syntax.Word as plain text when it is made up of
+// literal parts only (no expansions) - enough to compare a command name or
+// flag argument against an ast_match predicate.
+func wordLiteral(w *syntax.Word) string {
+	var sb strings.Builder
+	for _, part := range w.Parts {
+		if lit, ok := part.(*syntax.Lit); ok {
+			sb.WriteString(lit.Value)
+		}
+	}
+	return sb.String()
+}
+
+// nodeSource returns the exact source text a node spans, used as the
+// diagnostic's MatchedStr. Multi-line nodes fall back to an empty string
+// since there is no single line to highlight.
+func nodeSource(node syntax.Node, lines []string) string {
+	start, end := node.Pos(), node.End()
+	if start.Line() != end.Line() {
+		return ""
+	}
+
+	idx := int(start.Line()) - 1
+	if idx < 0 || idx >= len(lines) {
+		return ""
+	}
+
+	line := lines[idx]
+	from, to := int(start.Col())-1, int(end.Col())-1
+	if from < 0 || to > len(line) || from > to {
+		return ""
+	}
+
+	return line[from:to]
+}
@@ -0,0 +1,352 @@
+// Project:   macbash
+// File:      internal/scanner/ast_test.go
+// Purpose:   Tests for AST-backed rule matching
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+func TestScanFile_ASTCallExpr(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+# grep -P is fine in a comment
+grep -P '\d+' file.txt
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "ast-grep-perl",
+				Name:     "grep -P (AST)",
+				Severity: rules.SeverityError,
+				ASTMatch: &rules.ASTMatch{
+					Kind:    "CallExpr",
+					Command: "grep",
+					Flag:    "-P",
+				},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	// The comment on line 2 must not be reported: it isn't a CallExpr node.
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Line != 3 {
+		t.Errorf("expected match on line 3, got line %d", matches[0].Line)
+	}
+}
+
+func TestScanFile_ASTParamExp(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+name="world"
+echo "${name^^}"
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "ast-uppercase-all",
+				Name:     "bash4 case conversion",
+				Severity: rules.SeverityWarning,
+				ASTMatch: &rules.ASTMatch{
+					Kind:     "ParamExp",
+					Operator: "^^",
+				},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Line != 3 {
+		t.Errorf("expected match on line 3, got line %d", matches[0].Line)
+	}
+}
+
+func TestNew_ASTRulesSkipRegexCompile(t *testing.T) {
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "ast-only",
+				Name:     "AST only rule",
+				Severity: rules.SeverityInfo,
+				ASTMatch: &rules.ASTMatch{Kind: "TestClause"},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if len(s.compiled) != 0 {
+		t.Errorf("expected 0 compiled regex rules, got %d", len(s.compiled))
+	}
+	if len(s.ast.nodeRules) != 1 {
+		t.Errorf("expected 1 AST node rule, got %d", len(s.ast.nodeRules))
+	}
+	if len(s.ast.pipelineRules) != 0 {
+		t.Errorf("expected 0 AST pipeline rules, got %d", len(s.ast.pipelineRules))
+	}
+}
+
+func TestScanFile_ASTNegatedTest(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+x="$1"
+if ! test -z "$x"; then
+  echo not empty
+fi
+if test -z "$x"; then
+  echo still fine
+fi
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "negated-test-command",
+				Name:     "negated test command",
+				Severity: rules.SeverityInfo,
+				ASTMatch: &rules.ASTMatch{Kind: "Stmt", Command: "test", Negated: true},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	// Only the negated `! test -z` on line 3 should match; the plain
+	// `test -z` on line 6 isn't negated.
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Line != 3 {
+		t.Errorf("expected match on line 3, got line %d", matches[0].Line)
+	}
+}
+
+func TestScanFile_ASTNegatedBracketTest(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+x="$1"
+if ! [ -z "$x" ]; then
+  echo not empty
+fi
+if [ -z "$x" ]; then
+  echo still fine
+fi
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "negated-bracket-test",
+				Name:     "negated [ test",
+				Severity: rules.SeverityInfo,
+				ASTMatch: &rules.ASTMatch{Kind: "Stmt", Command: "[", Negated: true},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	// Only the negated `! [ -z ]` on line 3 should match; the plain
+	// `[ -z ]` on line 6 isn't negated.
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Line != 3 {
+		t.Errorf("expected match on line 3, got line %d", matches[0].Line)
+	}
+}
+
+func TestScanFile_ASTNegatedExtendedTest(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+x="$1"
+if ! [[ -z "$x" ]]; then
+  echo not empty
+fi
+if [[ -z "$x" ]]; then
+  echo still fine
+fi
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "negated-extended-test",
+				Name:     "negated [[ test",
+				Severity: rules.SeverityInfo,
+				ASTMatch: &rules.ASTMatch{Kind: "Stmt", Command: "[[", Negated: true},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	// Only the negated `! [[ -z ]]` on line 3 should match; the plain
+	// `[[ -z ]]` on line 6 isn't negated. [[ ]] parses as a TestClause, not
+	// a CallExpr, so this exercises the other branch of matchNegatedStmt.
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Line != 3 {
+		t.Errorf("expected match on line 3, got line %d", matches[0].Line)
+	}
+}
+
+func TestScanFile_ASTSilentPipeline(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+grep error log.txt | while read -r line; do
+  echo "$line"
+done
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "silent-pipeline-no-pipefail",
+				Name:     "pipeline can fail silently without pipefail",
+				Severity: rules.SeverityWarning,
+				ASTMatch: &rules.ASTMatch{Kind: "Pipeline"},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+}
+
+func TestScanFile_ASTSilentPipeline_SuppressedByPipefail(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+set -euo pipefail
+grep error log.txt | while read -r line; do
+  echo "$line"
+done
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "silent-pipeline-no-pipefail",
+				Name:     "pipeline can fail silently without pipefail",
+				Severity: rules.SeverityWarning,
+				ASTMatch: &rules.ASTMatch{Kind: "Pipeline"},
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	if len(matches) != 0 {
+		t.Fatalf("expected 0 matches once pipefail is set, got %d", len(matches))
+	}
+}
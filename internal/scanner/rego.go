@@ -0,0 +1,233 @@
+// Project:   macbash
+// File:      internal/scanner/rego.go
+// Purpose:   Rego/OPA-backed scanning for context-aware rule checks
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/open-policy-agent/opa/rego"
+	"mvdan.cc/sh/v3/syntax"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+// regoScanner evaluates rules.Rule entries with Type == rules.RuleTypeRego
+// against a per-file input document, rather than a line-oriented Pattern
+// or a single-node ast_match predicate. Unlike those, a Rego policy sees
+// the whole file at once, so it can express checks like "only flag
+// `realpath` when no earlier `command -v realpath` guard exists in the
+// same file" that are awkward as a regex + negative_pattern pair.
+type regoScanner struct {
+	compiled []*compiledRego
+}
+
+type compiledRego struct {
+	rule  *rules.Rule
+	query rego.PreparedEvalQuery
+}
+
+// regoInput is the document each policy sees as `input`: the shebang line
+// (if any), every command's argv in source order, and the name/value of
+// every plain `name=value` assignment, each tagged with the line it appears
+// on. Assignments made through declare, local, export and the like are not
+// included.
+type regoInput struct {
+	Shebang     string           `json:"shebang,omitempty"`
+	Commands    []regoCommand    `json:"commands"`
+	Assignments []regoAssignment `json:"assignments"`
+}
+
+type regoCommand struct {
+	Line int      `json:"line"`
+	Argv []string `json:"argv"`
+}
+
+type regoAssignment struct {
+	Line  int    `json:"line"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// newRegoScanner prepares one Eval query per rego rule up front, so
+// ScanFile only has to run the query, not recompile the policy, per file.
+func newRegoScanner(regoRules []*rules.Rule) (*regoScanner, error) {
+	s := &regoScanner{}
+	ctx := context.Background()
+
+	for _, r := range regoRules {
+		query, err := rego.New(
+			rego.Query("data.macbash.violations"),
+			rego.Module(r.ID+".rego", r.Rego),
+		).PrepareForEval(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("preparing rego rule %s: %w", r.ID, err)
+		}
+		s.compiled = append(s.compiled, &compiledRego{rule: r, query: query})
+	}
+
+	return s, nil
+}
+
+func (s *regoScanner) scan(path, content string) ([]rules.Match, error) {
+	if len(s.compiled) == 0 {
+		return nil, nil
+	}
+
+	lines := strings.Split(content, "\n")
+
+	input, err := buildRegoInput(path, content)
+	if err != nil {
+		// Syntax errors are reported by the fixer's validation path, not
+		// the scanner; rego rules simply find nothing to evaluate.
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	var matches []rules.Match
+
+	for _, cr := range s.compiled {
+		resultSet, err := cr.query.Eval(ctx, rego.EvalInput(input))
+		if err != nil {
+			return nil, fmt.Errorf("evaluating rego rule %s on %s: %w", cr.rule.ID, path, err)
+		}
+		matches = append(matches, violationsToMatches(cr.rule, path, lines, resultSet)...)
+	}
+
+	return matches, nil
+}
+
+// buildRegoInput parses content into a regoInput document: the shebang
+// line, every command's argv, and every plain `name=value` assignment, using the
+// same mvdan.cc/sh/v3 syntax tree the AST scanner walks.
+func buildRegoInput(path, content string) (regoInput, error) {
+	var doc regoInput
+
+	if strings.HasPrefix(content, "#!") {
+		if nl := strings.IndexByte(content, '\n'); nl >= 0 {
+			doc.Shebang = content[:nl]
+		} else {
+			doc.Shebang = content
+		}
+	}
+
+	parser := syntax.NewParser(syntax.Variant(syntax.LangBash), syntax.KeepComments(true))
+	file, err := parser.Parse(strings.NewReader(content), path)
+	if err != nil {
+		return regoInput{}, err
+	}
+
+	syntax.Walk(file, func(node syntax.Node) bool {
+		call, ok := node.(*syntax.CallExpr)
+		if !ok {
+			return true
+		}
+
+		line := int(call.Pos().Line())
+
+		for _, assign := range call.Assigns {
+			doc.Assignments = append(doc.Assignments, regoAssignment{
+				Line:  line,
+				Name:  assign.Name.Value,
+				Value: wordLiteral(assign.Value),
+			})
+		}
+
+		if len(call.Args) == 0 {
+			return true
+		}
+
+		argv := make([]string, len(call.Args))
+		for i, arg := range call.Args {
+			argv[i] = wordLiteral(arg)
+		}
+		doc.Commands = append(doc.Commands, regoCommand{Line: line, Argv: argv})
+
+		return true
+	})
+
+	return doc, nil
+}
+
+// violationsToMatches reads `data.macbash.violations` - a set of objects,
+// each carrying at least `line` and `matched`, optionally `column` and
+// `fix` - out of the query's result set and turns each into a Match.
+func violationsToMatches(rule *rules.Rule, path string, lines []string, rs rego.ResultSet) []rules.Match {
+	var matches []rules.Match
+
+	for _, result := range rs {
+		for _, expr := range result.Expressions {
+			items, ok := expr.Value.([]interface{})
+			if !ok {
+				continue
+			}
+			for _, item := range items {
+				v, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				matches = append(matches, violationToMatch(rule, path, lines, v))
+			}
+		}
+	}
+
+	return matches
+}
+
+func violationToMatch(rule *rules.Rule, path string, lines []string, v map[string]interface{}) rules.Match {
+	line := regoIntField(v, "line", 1)
+	column := regoIntField(v, "column", 1)
+
+	content := ""
+	if idx := line - 1; idx >= 0 && idx < len(lines) {
+		content = lines[idx]
+	}
+
+	match := rules.Match{
+		Rule:       rule,
+		File:       path,
+		Line:       line,
+		Column:     column,
+		Content:    content,
+		MatchedStr: regoStringField(v, "matched"),
+	}
+
+	if fix := regoStringField(v, "fix"); fix != "" {
+		match.FixedStr = fix
+	}
+
+	return match
+}
+
+// regoIntField and regoStringField read a violation object's fields - OPA
+// decodes Rego numbers as json.Number (and, depending on the decode path,
+// sometimes plain float64) and everything else as the Go type you'd expect
+// from encoding/json - falling back to a default when the field is absent
+// or the wrong type rather than failing the whole scan.
+func regoIntField(v map[string]interface{}, key string, fallback int) int {
+	switch n := v[key].(type) {
+	case json.Number:
+		i, err := n.Int64()
+		if err != nil {
+			return fallback
+		}
+		return int(i)
+	case float64:
+		return int(n)
+	default:
+		return fallback
+	}
+}
+
+func regoStringField(v map[string]interface{}, key string) string {
+	s, _ := v[key].(string)
+	return s
+}
@@ -0,0 +1,113 @@
+// Project:   macbash
+// File:      internal/scanner/rego_test.go
+// Purpose:   Tests for Rego/OPA-backed rule matching
+// Language:  Go
+//
+// License:   Apache-2.0
+// Copyright: (c) 2025 HyperSec Pty Ltd
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hypersec-io/macbash/internal/rules"
+)
+
+// sedInplacePolicy flags `sed -i` invocations whose `-i` argument has no
+// backup extension attached: any sed command with a standalone "-i" argv
+// token is flagged, while `sed -i.bak` is one token and is not. This is the same
+// GNU/BSD incompatibility the regex rule would need a negative_pattern to
+// express - except here it's a plain argv inspection.
+const sedInplacePolicy = `package macbash
+
+violations[v] {
+	some c
+	input.commands[c].argv[0] == "sed"
+	arg := input.commands[c].argv[_]
+	arg == "-i"
+	v := {"line": input.commands[c].line, "matched": "sed -i", "column": 1}
+}
+`
+
+func TestScanFile_RegoSedInplace(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+sed -i 's/foo/bar/' file.txt
+sed -i.bak 's/foo/bar/' file.txt
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "rego-sed-inplace",
+				Name:     "sed -i without backup extension (rego)",
+				Severity: rules.SeverityError,
+				Type:     rules.RuleTypeRego,
+				Rego:     sedInplacePolicy,
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	// Only the bare `sed -i` on line 2 lacks a backup extension; line 3's
+	// `sed -i.bak` is a single argv token and never equals "-i".
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d", len(matches))
+	}
+	if matches[0].Line != 2 {
+		t.Errorf("expected match on line 2, got line %d", matches[0].Line)
+	}
+}
+
+func TestScanFile_RegoNoMatch(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "test.sh")
+	content := `#!/bin/bash
+sed -i.bak 's/foo/bar/' file.txt
+`
+	if err := os.WriteFile(testFile, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	rs := &rules.RuleSet{
+		Rules: []rules.Rule{
+			{
+				ID:       "rego-sed-inplace",
+				Name:     "sed -i without backup extension (rego)",
+				Severity: rules.SeverityError,
+				Type:     rules.RuleTypeRego,
+				Rego:     sedInplacePolicy,
+			},
+		},
+	}
+
+	s, err := New(rs)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	matches, err := s.ScanFile(testFile)
+	if err != nil {
+		t.Fatalf("ScanFile() error = %v", err)
+	}
+
+	if len(matches) != 0 {
+		t.Fatalf("expected 0 matches, got %d", len(matches))
+	}
+}
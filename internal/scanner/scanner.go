@@ -18,8 +18,11 @@ import (
 )
 
 type Scanner struct {
+	ruleSet  *rules.RuleSet
 	rules    []rules.Rule
 	compiled map[string]*compiledRule
+	ast      *astScanner
+	rego     *regoScanner
 }
 
 type compiledRule struct {
@@ -30,13 +33,31 @@ type compiledRule struct {
 
 func New(ruleSet *rules.RuleSet) (*Scanner, error) {
 	s := &Scanner{
+		ruleSet:  ruleSet,
 		rules:    ruleSet.Rules,
 		compiled: make(map[string]*compiledRule),
 	}
 
-	// Pre-compile all patterns
+	var astRules []*rules.Rule
+	var regoRules []*rules.Rule
+
+	// Pre-compile all patterns. Rules carrying an ast_match block are
+	// dispatched to the AST scanner instead and have no pattern to compile;
+	// rules of type rego are dispatched to the rego scanner and have
+	// neither.
 	for i := range s.rules {
 		r := &s.rules[i]
+
+		if r.Type == rules.RuleTypeRego {
+			regoRules = append(regoRules, r)
+			continue
+		}
+
+		if r.ASTMatch != nil {
+			astRules = append(astRules, r)
+			continue
+		}
+
 		cr := &compiledRule{rule: r}
 
 		var err error
@@ -55,18 +76,26 @@ func New(ruleSet *rules.RuleSet) (*Scanner, error) {
 		s.compiled[r.ID] = cr
 	}
 
+	s.ast = newASTScanner(astRules)
+
+	rego, err := newRegoScanner(regoRules)
+	if err != nil {
+		return nil, err
+	}
+	s.rego = rego
+
 	return s, nil
 }
 
 func (s *Scanner) ScanFile(path string) ([]rules.Match, error) {
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	content := string(data)
 
 	var matches []rules.Match
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(strings.NewReader(content))
 	lineNum := 0
 
 	for scanner.Scan() {
@@ -121,9 +150,24 @@ func (s *Scanner) ScanFile(path string) ([]rules.Match, error) {
 		return nil, err
 	}
 
+	astMatches, err := s.ast.scan(path, content)
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, astMatches...)
+
+	regoMatches, err := s.rego.scan(path, content)
+	if err != nil {
+		return nil, err
+	}
+	matches = append(matches, regoMatches...)
+
 	return matches, nil
 }
 
+// ScanFiles scans each path and applies the ruleset's severity policy (if
+// any) to that file's matches - overriding severity or dropping disabled
+// rules - before they're combined across files.
 func (s *Scanner) ScanFiles(paths []string) ([]rules.Match, error) {
 	var allMatches []rules.Match
 
@@ -132,6 +176,7 @@ func (s *Scanner) ScanFiles(paths []string) ([]rules.Match, error) {
 		if err != nil {
 			return nil, err
 		}
+		matches = s.ruleSet.ApplyPolicy(path, matches)
 		allMatches = append(allMatches, matches...)
 	}
 